@@ -11,12 +11,22 @@ import (
 	"golang.org/x/oauth2"
 
 	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+	"github.com/brpaz/github-notifications-cleaner/internal/config"
 )
 
 const (
-	flagToken  = "token"
-	flagDays   = "days-threshold"
-	flagDryRun = "dry-run"
+	flagToken     = "token"
+	flagDays      = "days-threshold"
+	flagOlderThan = "older-than"
+	flagDryRun    = "dry-run"
+	flagIgnore    = "ignore-closed"
+	flagBatchRead = "batch-read"
+	flagConfig    = "config"
+
+	flagRepo        = "repo"
+	flagExcludeRepo = "exclude-repo"
+	flagOrg         = "org"
+	flagExcludeOrg  = "exclude-org"
 )
 
 // Cleaner defines the interface for the service that cleans up notifications.
@@ -27,9 +37,11 @@ type Cleaner interface {
 // NewCleanCmd creates a new instance of the clean command.
 func NewCleanCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "clean",
-		Short:   "Cleans up GitHub notifications.",
-		Example: `github-notifications-cleaner clean --token <GITHUB_TOKEN> --days-threshold 15`,
+		Use:   "clean",
+		Short: "Cleans up GitHub notifications.",
+		Example: `github-notifications-cleaner clean --token <GITHUB_TOKEN> --days-threshold 15
+github-notifications-cleaner clean --token <GITHUB_TOKEN> --older-than 1d12h
+github-notifications-cleaner clean --token <GITHUB_TOKEN> --org my-org --exclude-repo "my-org/sandbox-*"`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if cmd.Flags().Changed(flagToken) {
 				return nil
@@ -42,8 +54,17 @@ func NewCleanCmd() *cobra.Command {
 
 	cmd.Flags().StringP(flagToken, "t", "", "GitHub Personal Access Token with notifications access")
 	cmd.Flags().IntP(flagDays, "d", cleaner.DefaultDaysThreshold, "Mark notifications older than this number of days as done.")
+	cmd.Flags().String(flagOlderThan, "", `Mark notifications older than this duration as done, for example "36h", "2w" or "1d12h".`)
 	cmd.Flags().BoolP(flagDryRun, "n", false, "Dry run mode")
-
+	cmd.Flags().Bool(flagIgnore, false, "Also ignore the threads of closed issues and pull requests, so that later comments do not notify again.")
+	cmd.Flags().Bool(flagBatchRead, false, "Mark the threads of rules with the read action as read with repository-wide calls.")
+	cmd.Flags().StringSlice(flagRepo, nil, "Only process notifications from repositories matching this glob pattern (owner/repo). Can be repeated.")
+	cmd.Flags().StringSlice(flagExcludeRepo, nil, "Ignore notifications from repositories matching this glob pattern (owner/repo). Can be repeated.")
+	cmd.Flags().StringSlice(flagOrg, nil, "Only process notifications from organizations matching this glob pattern. Can be repeated.")
+	cmd.Flags().StringSlice(flagExcludeOrg, nil, "Ignore notifications from organizations matching this glob pattern. Can be repeated.")
+	cmd.Flags().StringP(flagConfig, "c", config.DefaultPath(), "Path to the configuration file (YAML or TOML)")
+
+	cmd.MarkFlagsMutuallyExclusive(flagDays, flagOlderThan)
 	_ = cmd.MarkFlagRequired(flagToken)
 	return cmd
 }
@@ -58,14 +79,14 @@ func initCleaner(cmd *cobra.Command) (Cleaner, context.Context, error) {
 		return nil, nil, fmt.Errorf("GitHub token is required")
 	}
 
-	daysThreshold, err := cmd.Flags().GetInt(flagDays)
+	cfg, err := loadConfig(cmd)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	dryRun, err := cmd.Flags().GetBool(flagDryRun)
+	rules, err := cfg.BuildRules()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, fmt.Errorf("error building rules from config: %w", err)
 	}
 
 	ctx := context.Background()
@@ -73,14 +94,116 @@ func initCleaner(cmd *cobra.Command) (Cleaner, context.Context, error) {
 	tc := oauth2.NewClient(ctx, ts)
 
 	ghClient := github.NewClient(tc)
-	nc := cleaner.NewNotificationsCleaner(
+	opts := []cleaner.Option{
 		cleaner.WithGitHubClient(ghClient),
-		cleaner.WithOlderThanDays(daysThreshold),
-		cleaner.WithDryRun(dryRun),
-	)
+		cleaner.WithOlderThan(cfg.Threshold()),
+		cleaner.WithDryRun(cfg.DryRun),
+		cleaner.WithFilter(cfg.Filter()),
+		cleaner.WithRules(rules...),
+		cleaner.WithNoisyRepos(cfg.NoisyReposOptions()),
+		cleaner.WithBatchRead(cfg.BatchRead),
+	}
+	if cfg.Journal != "" {
+		opts = append(opts, cleaner.WithRecorder(cleaner.NewFileRecorder(cfg.Journal)))
+	}
+	nc := cleaner.NewNotificationsCleaner(opts...)
 	return nc, ctx, nil
 }
 
+// loadConfig loads the configuration file and applies the values of the flags
+// explicitly set in the command line on top of it.
+// A missing file is only an error when the config flag was explicitly set.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	configPath, err := cmd.Flags().GetString(flagConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.LoadOrDefault(configPath, cmd.Flags().Changed(flagConfig))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyFlags(cmd, cfg); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// applyFlags applies the values of the flags explicitly set in the command line to the configuration.
+func applyFlags(cmd *cobra.Command, cfg *config.Config) error {
+	if err := applyThresholdFlags(cmd, cfg); err != nil {
+		return err
+	}
+
+	boolFlags := map[string]*bool{
+		flagDryRun:    &cfg.DryRun,
+		flagIgnore:    &cfg.IgnoreClosed,
+		flagBatchRead: &cfg.BatchRead,
+	}
+	for flag, value := range boolFlags {
+		if !cmd.Flags().Changed(flag) {
+			continue
+		}
+		var err error
+		if *value, err = cmd.Flags().GetBool(flag); err != nil {
+			return err
+		}
+	}
+
+	filterFlags := map[string]*[]string{
+		flagRepo:        &cfg.Filters.Repos,
+		flagExcludeRepo: &cfg.Filters.ExcludeRepos,
+		flagOrg:         &cfg.Filters.Orgs,
+		flagExcludeOrg:  &cfg.Filters.ExcludeOrgs,
+	}
+	for flag, value := range filterFlags {
+		if !cmd.Flags().Changed(flag) {
+			continue
+		}
+		var err error
+		if *value, err = cmd.Flags().GetStringSlice(flag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyThresholdFlags applies the days threshold and older than flags to the configuration.
+func applyThresholdFlags(cmd *cobra.Command, cfg *config.Config) error {
+	var err error
+	if cmd.Flags().Changed(flagDays) {
+		if cfg.DaysThreshold, err = cmd.Flags().GetInt(flagDays); err != nil {
+			return err
+		}
+		cfg.OlderThan = 0
+	}
+
+	if cmd.Flags().Changed(flagOlderThan) {
+		value, err := cmd.Flags().GetString(flagOlderThan)
+		if err != nil {
+			return err
+		}
+		olderThan, err := cleaner.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid --%s: %w", flagOlderThan, err)
+		}
+		// A zero duration would be taken as unset, falling back to the default threshold.
+		if olderThan == 0 {
+			return fmt.Errorf("invalid --%s: duration must be positive", flagOlderThan)
+		}
+		cfg.OlderThan = config.Duration(olderThan)
+	}
+
+	return nil
+}
+
 func run(cmd *cobra.Command, args []string) error {
 	cleanerInstance, ctx, err := initCleaner(cmd)
 	if err != nil {
@@ -0,0 +1,89 @@
+package clean
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/config"
+)
+
+// writeConfig writes a configuration file with the given content and returns the config flag pointing to it.
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	return "--" + flagConfig + "=" + p
+}
+
+// parseConfig parses the command line arguments and loads the configuration.
+func parseConfig(t *testing.T, args ...string) (*config.Config, error) {
+	t.Helper()
+	cmd := NewCleanCmd()
+	require.NoError(t, cmd.ParseFlags(args))
+	return loadConfig(cmd)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("keeps file values without flags", func(t *testing.T) {
+		cfg, err := parseConfig(t, writeConfig(t, `
+dry_run: true
+ignore_closed: true
+older_than: 2w
+filters:
+  repos: ["my-org/*"]
+`))
+		require.NoError(t, err)
+		assert.True(t, cfg.DryRun)
+		assert.True(t, cfg.IgnoreClosed)
+		assert.Equal(t, 14*24*time.Hour, cfg.Threshold())
+		assert.Equal(t, []string{"my-org/*"}, cfg.Filters.Repos)
+	})
+
+	t.Run("overrides file values with flags", func(t *testing.T) {
+		cfg, err := parseConfig(t, writeConfig(t, `
+dry_run: true
+ignore_closed: true
+batch_read: false
+filters:
+  repos: ["my-org/*"]
+  exclude_orgs: ["spam-org"]
+`),
+			"--dry-run=false",
+			"--batch-read",
+			"--repo", "other-org/*",
+			"--exclude-org", "noisy-org",
+		)
+		require.NoError(t, err)
+		assert.False(t, cfg.DryRun)
+		assert.True(t, cfg.BatchRead)
+		assert.True(t, cfg.IgnoreClosed, "expected values without flags to be kept")
+		assert.Equal(t, []string{"other-org/*"}, cfg.Filters.Repos)
+		assert.Equal(t, []string{"noisy-org"}, cfg.Filters.ExcludeOrgs)
+	})
+
+	t.Run("days threshold flag clears the file duration", func(t *testing.T) {
+		cfg, err := parseConfig(t, writeConfig(t, "older_than: 2w"), "--days-threshold", "5")
+		require.NoError(t, err)
+		assert.Equal(t, 5, cfg.DaysThreshold)
+		assert.Zero(t, cfg.OlderThan)
+		assert.Equal(t, 5*24*time.Hour, cfg.Threshold())
+	})
+
+	t.Run("older than flag overrides the file days threshold", func(t *testing.T) {
+		cfg, err := parseConfig(t, writeConfig(t, "days_threshold: 10"), "--older-than", "1d12h")
+		require.NoError(t, err)
+		assert.Equal(t, 36*time.Hour, cfg.Threshold())
+	})
+
+	t.Run("returns error for zero older than", func(t *testing.T) {
+		for _, value := range []string{"0", "0h"} {
+			_, err := parseConfig(t, writeConfig(t, ""), "--older-than", value)
+			assert.Error(t, err, value)
+		}
+	})
+}
@@ -0,0 +1,136 @@
+// Package restore contains the command that reverts the subscription changes made by the clean command.
+package restore
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+	"github.com/brpaz/github-notifications-cleaner/internal/config"
+)
+
+const (
+	flagToken   = "token"
+	flagJournal = "journal"
+	flagDryRun  = "dry-run"
+	flagConfig  = "config"
+)
+
+// NewRestoreCmd creates a new instance of the restore command.
+func NewRestoreCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore [run-id]",
+		Short: "Restores the subscriptions changed by a run of the clean command.",
+		Long: `Restores the thread and repository subscriptions changed by a run of the clean command,
+using the changes recorded in the journal. Without a run ID, lists the runs in the journal.
+Notifications marked as done cannot be restored.`,
+		Example: `github-notifications-cleaner restore
+github-notifications-cleaner restore --token <GITHUB_TOKEN> 20250101T120000Z`,
+		Args: cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed(flagToken) {
+				return nil
+			}
+			_ = cmd.Flags().Set(flagToken, os.Getenv("GITHUB_TOKEN"))
+			return nil
+		},
+		RunE: run,
+	}
+
+	cmd.Flags().StringP(flagToken, "t", "", "GitHub Personal Access Token with notifications access")
+	cmd.Flags().String(flagJournal, config.DefaultJournalPath(), "Path to the journal of subscription changes. Overrides the journal of the configuration file.")
+	cmd.Flags().BoolP(flagDryRun, "n", false, "Dry run mode")
+	cmd.Flags().StringP(flagConfig, "c", config.DefaultPath(), "Path to the configuration file (YAML or TOML)")
+	return cmd
+}
+
+func run(cmd *cobra.Command, args []string) error {
+	journal, err := journalPath(cmd)
+	if err != nil {
+		return err
+	}
+
+	changes, err := cleaner.ReadJournal(journal)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		return listRuns(cmd, changes)
+	}
+
+	runID := args[0]
+	if !slices.Contains(cleaner.Runs(changes), runID) {
+		return fmt.Errorf("run %s not found in journal %s", runID, journal)
+	}
+
+	githubToken, err := cmd.Flags().GetString(flagToken)
+	if err != nil {
+		return err
+	}
+	if githubToken == "" {
+		return fmt.Errorf("GitHub token is required")
+	}
+
+	dryRun, err := cmd.Flags().GetBool(flagDryRun)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: githubToken})
+	ghClient := github.NewClient(oauth2.NewClient(ctx, ts))
+
+	restored, err := cleaner.Restore(ctx, ghClient, changes, runID, dryRun)
+	if err != nil {
+		return fmt.Errorf("error restoring subscriptions: %w", err)
+	}
+
+	slog.Info("Subscriptions restored successfully.", slog.String("run_id", runID), slog.Int("restored", restored))
+	return nil
+}
+
+// journalPath returns the path of the journal: the journal flag when explicitly set,
+// or the journal of the configuration file, which defaults to config.DefaultJournalPath.
+func journalPath(cmd *cobra.Command) (string, error) {
+	if cmd.Flags().Changed(flagJournal) {
+		return cmd.Flags().GetString(flagJournal)
+	}
+
+	configPath, err := cmd.Flags().GetString(flagConfig)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, err := config.LoadOrDefault(configPath, cmd.Flags().Changed(flagConfig))
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.Journal == "" {
+		return "", fmt.Errorf("no journal configured")
+	}
+	return cfg.Journal, nil
+}
+
+// listRuns prints the runs in the journal, with the number of changes of each run.
+func listRuns(cmd *cobra.Command, changes []cleaner.SubscriptionChange) error {
+	counts := make(map[string]int)
+	for _, c := range changes {
+		counts[c.RunID]++
+	}
+
+	for _, runID := range cleaner.Runs(changes) {
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "%s\t%d changes\n", runID, counts[runID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/brpaz/github-notifications-cleaner/cmd/clean"
+	"github.com/brpaz/github-notifications-cleaner/cmd/restore"
 	"github.com/brpaz/github-notifications-cleaner/cmd/version"
 )
 
@@ -18,6 +19,7 @@ func NewRootCmd() *cobra.Command {
 	// Reggister subcommands
 	rootCmd.AddCommand(version.NewCmd())
 	rootCmd.AddCommand(clean.NewCleanCmd())
+	rootCmd.AddCommand(restore.NewRestoreCmd())
 
 	return rootCmd
 }
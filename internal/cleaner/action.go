@@ -0,0 +1,101 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Action is an operation applied to the notification threads cleaned by a rule.
+type Action string
+
+// Supported actions.
+const (
+	// ActionUnsubscribe deletes the thread subscription, so that only new
+	// mentions or activity involving the user notify again.
+	ActionUnsubscribe Action = "unsubscribe"
+	// ActionIgnore sets the thread subscription to ignored, muting all future notifications of the thread.
+	ActionIgnore Action = "ignore"
+	// ActionRead marks the thread as read, keeping it in the inbox.
+	ActionRead Action = "read"
+	// ActionDone marks the thread as done, removing it from the inbox.
+	ActionDone Action = "done"
+)
+
+// Actions lists all the supported actions, in the order they are applied.
+var Actions = []Action{ActionUnsubscribe, ActionIgnore, ActionRead, ActionDone}
+
+// ParseActions parses and validates a list of actions.
+func ParseActions(values []string) ([]Action, error) {
+	actions := make([]Action, 0, len(values))
+	for _, v := range values {
+		action := Action(v)
+		if !slices.Contains(Actions, action) {
+			return nil, fmt.Errorf("unknown action %q", v)
+		}
+		actions = append(actions, action)
+	}
+
+	if slices.Contains(actions, ActionUnsubscribe) && slices.Contains(actions, ActionIgnore) {
+		return nil, errors.New("actions unsubscribe and ignore cannot be combined")
+	}
+
+	return actions, nil
+}
+
+// ActionRule applies Actions, instead of marking threads as done,
+// to the notifications cleaned by the wrapped Rule.
+type ActionRule struct {
+	Rule
+	Actions []Action
+}
+
+// WithActions wraps the rule so that the notifications it cleans get the given actions.
+func WithActions(rule Rule, actions ...Action) *ActionRule {
+	return &ActionRule{Rule: rule, Actions: actions}
+}
+
+// Evaluate evaluates the wrapped rule and sets the actions of its done decisions.
+// When the wrapped rule ignores the thread (see ClosedSubjectRule.Ignore), the ignore action
+// is kept, unless the thread is unsubscribed instead.
+func (r *ActionRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	decision, err := r.Rule.Evaluate(ctx, t)
+	if err != nil || decision.Outcome != OutcomeDone {
+		return decision, err
+	}
+
+	actions := slices.Clone(r.Actions)
+	if slices.Contains(decision.Actions, ActionIgnore) && !slices.Contains(actions, ActionIgnore) && !slices.Contains(actions, ActionUnsubscribe) {
+		actions = append(actions, ActionIgnore)
+	}
+	decision.Actions = actions
+	return decision, nil
+}
+
+// applyAction applies the action to the notification thread with the given ID.
+func applyAction(ctx context.Context, client *github.Client, id string, action Action) error {
+	switch action {
+	case ActionUnsubscribe:
+		_, err := client.Activity.DeleteThreadSubscription(ctx, id)
+		return err
+	case ActionIgnore:
+		_, _, err := client.Activity.SetThreadSubscription(ctx, id, &github.Subscription{Ignored: github.Ptr(true)})
+		return err
+	case ActionRead:
+		_, err := client.Activity.MarkThreadRead(ctx, id)
+		return err
+	case ActionDone:
+		nID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return fmt.Errorf("error converting notification ID to int: %w", err)
+		}
+		_, err = client.Activity.MarkThreadDone(ctx, nID)
+		return err
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+}
@@ -0,0 +1,127 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestParseActions(t *testing.T) {
+	actions, err := cleaner.ParseActions([]string{"read", "ignore"})
+	require.NoError(t, err)
+	assert.Equal(t, []cleaner.Action{cleaner.ActionRead, cleaner.ActionIgnore}, actions)
+
+	_, err = cleaner.ParseActions([]string{"archive"})
+	assert.Error(t, err)
+
+	_, err = cleaner.ParseActions([]string{"unsubscribe", "ignore"})
+	assert.Error(t, err)
+}
+
+func TestActionRule(t *testing.T) {
+	ignored := cleaner.Done("closed")
+	ignored.Actions = []cleaner.Action{cleaner.ActionIgnore, cleaner.ActionDone}
+
+	testCases := []struct {
+		name     string
+		decision cleaner.Decision
+		actions  []cleaner.Action
+		expected []cleaner.Action
+	}{
+		{"replaces the done action", cleaner.Done("stub"), []cleaner.Action{cleaner.ActionRead}, []cleaner.Action{cleaner.ActionRead}},
+		{"keeps the ignore action of the rule", ignored, []cleaner.Action{cleaner.ActionRead}, []cleaner.Action{cleaner.ActionRead, cleaner.ActionIgnore}},
+		{"does not ignore unsubscribed threads", ignored, []cleaner.Action{cleaner.ActionUnsubscribe}, []cleaner.Action{cleaner.ActionUnsubscribe}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule := cleaner.WithActions(&stubRule{name: "stub", decision: tc.decision}, tc.actions...)
+			decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(nil, &github.Notification{}))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Actions)
+		})
+	}
+}
+
+func TestClean_Actions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		actions []cleaner.Action
+		mocks   func()
+	}{
+		{
+			name:    "marks threads as read",
+			actions: []cleaner.Action{cleaner.ActionRead},
+			mocks: func() {
+				gock.New("https://api.github.com").
+					Patch("/notifications/threads/1").
+					Reply(205)
+			},
+		},
+		{
+			name:    "unsubscribes and marks threads as done",
+			actions: []cleaner.Action{cleaner.ActionDone, cleaner.ActionUnsubscribe},
+			mocks: func() {
+				gock.New("https://api.github.com").
+					Delete("/notifications/threads/1/subscription").
+					Reply(204)
+				gock.New("https://api.github.com").
+					Delete("/notifications/threads/1").
+					Reply(204)
+			},
+		},
+		{
+			name:    "ignores threads",
+			actions: []cleaner.Action{cleaner.ActionIgnore, cleaner.ActionRead},
+			mocks: func() {
+				gock.New("https://api.github.com").
+					Put("/notifications/threads/1/subscription").
+					JSON(map[string]any{"ignored": true}).
+					Reply(200).
+					JSON(map[string]any{"ignored": true})
+				gock.New("https://api.github.com").
+					Patch("/notifications/threads/1").
+					Reply(205)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/notifications").
+				Reply(200).
+				JSON([]*github.Notification{
+					{ID: github.Ptr("1"), UpdatedAt: &github.Timestamp{Time: time.Now()}},
+				})
+			tc.mocks()
+
+			rule := &stubRule{name: "stub", decision: cleaner.Done("stub")}
+			nc := cleaner.NewNotificationsCleaner(
+				cleaner.WithGitHubClient(setupMockClient(t)),
+				cleaner.WithRules(cleaner.WithActions(rule, tc.actions...)),
+			)
+
+			err := nc.Clean(context.Background())
+			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
+		})
+	}
+
+	t.Run("does not apply actions to kept notifications", func(t *testing.T) {
+		rule := cleaner.WithActions(&stubRule{name: "stub", decision: cleaner.Keep("stub")}, cleaner.ActionIgnore)
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(nil, &github.Notification{}))
+		require.NoError(t, err)
+		assert.Empty(t, decision.Actions)
+		assert.Equal(t, "stub", rule.Name())
+	})
+}
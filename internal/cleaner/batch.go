@@ -0,0 +1,144 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// readBatch collects the threads to mark as read during a run, so that they can be
+// marked with a few repository-wide calls instead of one call per thread.
+type readBatch struct {
+	threads map[string]*Thread
+}
+
+// newReadBatch creates an empty readBatch.
+func newReadBatch() *readBatch {
+	return &readBatch{threads: make(map[string]*Thread)}
+}
+
+// add adds the thread to the batch.
+func (b *readBatch) add(t *Thread) {
+	b.threads[t.GetID()] = t
+}
+
+// flushReadBatch marks the threads of the batch as read.
+//
+// The bulk endpoints mark every notification updated before a cutoff as read, so the cutoff
+// of a repository is set before the earliest unread notification that must be left untouched
+// (the protected threads), and never after listedAt, to spare notifications received during the run.
+// Threads updated after the cutoff are marked one by one. When no thread is protected at all,
+// all the notifications are marked as read with a single call.
+func (nc *NotificationsCleaner) flushReadBatch(ctx context.Context, batch *readBatch, all []*github.Notification, listedAt time.Time) {
+	if len(batch.threads) == 0 {
+		return
+	}
+
+	protected := protectedThreads(batch, all)
+
+	byRepo := make(map[string][]*Thread)
+	for _, t := range batch.threads {
+		repo := t.GetRepository().GetFullName()
+		byRepo[repo] = append(byRepo[repo], t)
+	}
+
+	if len(protected) == 0 && len(byRepo) > 1 {
+		slog.Info("marking all notifications as read", slog.Time("last_read_at", listedAt))
+		_, err := nc.GitHubClient.Activity.MarkNotificationsRead(ctx, github.Timestamp{Time: listedAt})
+		if err = acceptedAsSuccess(err); err == nil {
+			return
+		}
+		slog.Error("error marking all notifications as read, falling back to repositories",
+			slog.String("error", err.Error()),
+		)
+	}
+
+	for _, repo := range slices.Sorted(maps.Keys(byRepo)) {
+		cutoff := listedAt
+		if at, ok := protected[repo]; ok && at.Before(cutoff) {
+			// The bulk endpoint behavior for notifications updated exactly at the cutoff
+			// is not documented, so keep a margin.
+			cutoff = at.Add(-time.Second)
+		}
+		nc.flushRepositoryReads(ctx, repo, byRepo[repo], cutoff)
+	}
+}
+
+// protectedThreads returns the time of the earliest unread notification not in the batch, per repository.
+func protectedThreads(batch *readBatch, all []*github.Notification) map[string]time.Time {
+	protected := make(map[string]time.Time)
+	for _, n := range all {
+		if _, ok := batch.threads[n.GetID()]; ok || !n.GetUnread() {
+			continue
+		}
+		repo := n.GetRepository().GetFullName()
+		if at, ok := protected[repo]; !ok || n.GetUpdatedAt().Before(at) {
+			protected[repo] = n.GetUpdatedAt().Time
+		}
+	}
+	return protected
+}
+
+// flushRepositoryReads marks the threads of the repository as read: the threads updated before the cutoff
+// with a repository-wide call, when there are several of them, and the other ones one by one.
+func (nc *NotificationsCleaner) flushRepositoryReads(ctx context.Context, repo string, threads []*Thread, cutoff time.Time) {
+	var covered, remaining []*Thread
+	for _, t := range threads {
+		if t.GetUpdatedAt().Before(cutoff) {
+			covered = append(covered, t)
+		} else {
+			remaining = append(remaining, t)
+		}
+	}
+
+	// A single thread costs a single call either way.
+	if len(covered) > 1 {
+		if err := nc.markRepositoryRead(ctx, repo, cutoff, len(covered)); err != nil {
+			slog.Error("error marking repository notifications as read, falling back to threads",
+				slog.String("repository", repo),
+				slog.String("error", err.Error()),
+			)
+			remaining = append(remaining, covered...)
+		}
+	} else {
+		remaining = append(remaining, covered...)
+	}
+
+	for _, t := range remaining {
+		if err := applyAction(ctx, nc.GitHubClient, t.GetID(), ActionRead); err != nil {
+			slog.Error("error applying action to notification",
+				slog.String("notification_id", t.GetID()),
+				slog.String("action", string(ActionRead)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// markRepositoryRead marks the notifications of the repository updated before the cutoff as read.
+func (nc *NotificationsCleaner) markRepositoryRead(ctx context.Context, repo string, cutoff time.Time, threads int) error {
+	slog.Info("marking repository notifications as read",
+		slog.String("repository", repo),
+		slog.Time("last_read_at", cutoff),
+		slog.Int("threads", threads),
+	)
+	owner, name := splitRepository(repo)
+	_, err := nc.GitHubClient.Activity.MarkRepositoryNotificationsRead(ctx, owner, name, github.Timestamp{Time: cutoff})
+	return acceptedAsSuccess(err)
+}
+
+// acceptedAsSuccess returns nil for a 202 Accepted response, which GitHub returns when there are
+// too many notifications to mark as read synchronously, and the error otherwise.
+func acceptedAsSuccess(err error) error {
+	var accepted *github.AcceptedError
+	if errors.As(err, &accepted) {
+		slog.Info("marking notifications as read is processed asynchronously by GitHub")
+		return nil
+	}
+	return err
+}
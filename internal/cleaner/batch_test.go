@@ -0,0 +1,131 @@
+package cleaner_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestClean_BatchRead(t *testing.T) {
+	notification := func(id, repo, reason string, age time.Duration) *github.Notification {
+		return &github.Notification{
+			ID:         github.Ptr(id),
+			Reason:     github.Ptr(reason),
+			Unread:     github.Ptr(true),
+			UpdatedAt:  &github.Timestamp{Time: time.Now().Add(-age)},
+			Repository: &github.Repository{FullName: github.Ptr(repo)},
+		}
+	}
+
+	rules := []cleaner.Rule{
+		cleaner.NewReasonRule([]string{cleaner.ReasonMention}, 0, cleaner.OutcomeKeep),
+		cleaner.WithActions(&stubRule{name: "read", decision: cleaner.Done("stub")}, cleaner.ActionRead),
+	}
+
+	testCases := []struct {
+		name          string
+		notifications []*github.Notification
+		mocks         func(notifications []*github.Notification)
+	}{
+		{
+			name: "marks all notifications as read when none is protected",
+			notifications: []*github.Notification{
+				notification("1", "owner/a", cleaner.ReasonSubscribed, 72*time.Hour),
+				notification("2", "owner/b", cleaner.ReasonSubscribed, 72*time.Hour),
+			},
+			mocks: func(notifications []*github.Notification) {
+				// 202 Accepted: GitHub marks the notifications asynchronously.
+				gock.New("https://api.github.com").
+					Put("/notifications").
+					AddMatcher(lastReadAtBetween(notifications[1].GetUpdatedAt().Time, time.Now().Add(time.Minute))).
+					Reply(202)
+			},
+		},
+		{
+			name: "marks repository notifications as read before protected threads",
+			notifications: []*github.Notification{
+				notification("1", "owner/a", cleaner.ReasonSubscribed, 72*time.Hour),
+				notification("2", "owner/a", cleaner.ReasonSubscribed, 72*time.Hour),
+				notification("3", "owner/a", cleaner.ReasonMention, 48*time.Hour),
+				notification("4", "owner/a", cleaner.ReasonSubscribed, 24*time.Hour),
+			},
+			mocks: func(notifications []*github.Notification) {
+				// The cutoff covers threads 1 and 2, but not the protected thread 3.
+				gock.New("https://api.github.com").
+					Put("/repos/owner/a/notifications").
+					AddMatcher(lastReadAtBetween(notifications[1].GetUpdatedAt().Time, notifications[2].GetUpdatedAt().Time)).
+					Reply(205)
+				// Thread 4 was updated after the protected thread 3, so it is marked on its own.
+				gock.New("https://api.github.com").
+					Patch("/notifications/threads/4").
+					Reply(205)
+			},
+		},
+		{
+			name: "marks single threads on their own",
+			notifications: []*github.Notification{
+				notification("1", "owner/a", cleaner.ReasonSubscribed, 72*time.Hour),
+				notification("2", "owner/a", cleaner.ReasonMention, 48*time.Hour),
+			},
+			mocks: func([]*github.Notification) {
+				gock.New("https://api.github.com").
+					Patch("/notifications/threads/1").
+					Reply(205)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/notifications").
+				Reply(200).
+				JSON(tc.notifications)
+			tc.mocks(tc.notifications)
+
+			nc := cleaner.NewNotificationsCleaner(
+				cleaner.WithGitHubClient(setupMockClient(t)),
+				cleaner.WithRules(rules...),
+				cleaner.WithBatchRead(true),
+			)
+
+			err := nc.Clean(context.Background())
+			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
+			assert.False(t, gock.HasUnmatchedRequest())
+		})
+	}
+}
+
+// lastReadAtBetween matches the requests marking notifications as read with a last_read_at
+// after the given time and strictly before the end time.
+func lastReadAtBetween(after, before time.Time) gock.MatchFunc {
+	return func(req *http.Request, _ *gock.Request) (bool, error) {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return false, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(data))
+
+		var body struct {
+			LastReadAt time.Time `json:"last_read_at"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return false, err
+		}
+		return body.LastReadAt.After(after) && body.LastReadAt.Before(before), nil
+	}
+}
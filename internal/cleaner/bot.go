@@ -0,0 +1,106 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// BotRule marks notifications of issues and pull requests created by bots as done,
+// unless the authenticated user was assigned or asked to review.
+// A bot is a user of type "Bot" (for example "dependabot[bot]") or one of Logins.
+type BotRule struct {
+	// Logins lists additional accounts considered as bots, such as Renovate self-hosted users.
+	Logins []string
+	// MaxAge restricts the rule to notifications not updated for longer than MaxAge.
+	// When zero, the rule applies regardless of the notification age.
+	MaxAge time.Duration
+}
+
+// Name returns the rule name.
+func (r *BotRule) Name() string {
+	return "bot"
+}
+
+// Evaluate checks the author of the issue or pull request related to the notification.
+func (r *BotRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	if reason := t.GetReason(); reason == ReasonAssign || reason == ReasonReviewRequested {
+		return Skip(), nil
+	}
+
+	author, involved, err := subjectAuthor(ctx, t)
+	if err != nil {
+		return Skip(), err
+	}
+
+	if !r.isBot(author) {
+		return Skip(), nil
+	}
+
+	if r.MaxAge > 0 && !olderThan(t, r.MaxAge) {
+		return Skip(), nil
+	}
+
+	viewerInvolved, err := involvesViewer(ctx, t, involved)
+	if err != nil {
+		return Skip(), err
+	}
+	if viewerInvolved {
+		return Skip(), nil
+	}
+
+	return Done(fmt.Sprintf("created by bot %s", author.GetLogin())), nil
+}
+
+// subjectAuthor returns the author of the issue or pull request related to the notification,
+// along with its assignees and requested reviewers. The author is nil for other subjects.
+func subjectAuthor(ctx context.Context, t *Thread) (author *github.User, involved []*github.User, err error) {
+	switch t.GetSubject().GetType() {
+	case TypePullRequest:
+		pr, err := t.PullRequest(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pr.GetUser(), slices.Concat(pr.Assignees, pr.RequestedReviewers), nil
+	case TypeIssue:
+		issue, err := t.Issue(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		return issue.GetUser(), issue.Assignees, nil
+	default:
+		return nil, nil, nil
+	}
+}
+
+// involvesViewer reports whether the authenticated user is one of the given users.
+func involvesViewer(ctx context.Context, t *Thread, users []*github.User) (bool, error) {
+	if len(users) == 0 {
+		return false, nil
+	}
+
+	login, err := t.Viewer().Login(ctx)
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(users, func(u *github.User) bool {
+		return u.GetLogin() == login
+	}), nil
+}
+
+// isBot reports whether the user is a bot.
+func (r *BotRule) isBot(u *github.User) bool {
+	if u == nil {
+		return false
+	}
+	if u.GetType() == "Bot" {
+		return true
+	}
+	return slices.ContainsFunc(r.Logins, func(login string) bool {
+		return strings.EqualFold(login, u.GetLogin())
+	})
+}
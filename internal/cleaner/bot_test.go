@@ -0,0 +1,114 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestBotRule(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rule      *cleaner.BotRule
+		reason    string
+		updatedAt time.Time
+		pr        map[string]any
+		expected  cleaner.Outcome
+	}{
+		{
+			name:      "marks pull requests from bot users as done",
+			rule:      &cleaner.BotRule{},
+			reason:    cleaner.ReasonSubscribed,
+			updatedAt: time.Now(),
+			pr:        map[string]any{"user": map[string]any{"login": "dependabot[bot]", "type": "Bot"}},
+			expected:  cleaner.OutcomeDone,
+		},
+		{
+			name:      "marks pull requests from allowlisted logins as done",
+			rule:      &cleaner.BotRule{Logins: []string{"Renovate-Self-Hosted"}},
+			reason:    cleaner.ReasonSubscribed,
+			updatedAt: time.Now(),
+			pr:        map[string]any{"user": map[string]any{"login": "renovate-self-hosted", "type": "User"}},
+			expected:  cleaner.OutcomeDone,
+		},
+		{
+			name:      "skips pull requests from humans",
+			rule:      &cleaner.BotRule{},
+			reason:    cleaner.ReasonSubscribed,
+			updatedAt: time.Now(),
+			pr:        map[string]any{"user": map[string]any{"login": "teammate", "type": "User"}},
+			expected:  cleaner.OutcomeSkip,
+		},
+		{
+			name:      "skips recent notifications",
+			rule:      &cleaner.BotRule{MaxAge: 12 * time.Hour},
+			reason:    cleaner.ReasonSubscribed,
+			updatedAt: time.Now(),
+			pr:        map[string]any{"user": map[string]any{"login": "dependabot[bot]", "type": "Bot"}},
+			expected:  cleaner.OutcomeSkip,
+		},
+		{
+			name:      "keeps pull requests assigned to the user",
+			rule:      &cleaner.BotRule{},
+			reason:    cleaner.ReasonSubscribed,
+			updatedAt: time.Now(),
+			pr: map[string]any{
+				"user":      map[string]any{"login": "dependabot[bot]", "type": "Bot"},
+				"assignees": []map[string]any{{"login": "me"}},
+			},
+			expected: cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/repos/owner/repo/pulls/1").
+				Reply(200).
+				JSON(tc.pr)
+
+			gock.New("https://api.github.com").
+				Get("/user").
+				Reply(200).
+				JSON(map[string]any{"login": "me"})
+
+			n := &github.Notification{
+				ID:        github.Ptr("1"),
+				Reason:    github.Ptr(tc.reason),
+				UpdatedAt: &github.Timestamp{Time: tc.updatedAt},
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypePullRequest),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/pulls/1"),
+				},
+			}
+
+			decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+		})
+	}
+
+	t.Run("skips review requested notifications without lookup", func(t *testing.T) {
+		n := &github.Notification{
+			ID:     github.Ptr("1"),
+			Reason: github.Ptr(cleaner.ReasonReviewRequested),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypePullRequest),
+				URL:  github.Ptr("https://api.github.com/repos/owner/repo/pulls/1"),
+			},
+		}
+
+		decision, err := (&cleaner.BotRule{}).Evaluate(context.Background(), cleaner.NewThread(nil, n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+	})
+}
@@ -0,0 +1,118 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// checkSuiteTitleRegex matches the title of CheckSuite notifications,
+// for example "CI workflow run failed for main branch".
+var checkSuiteTitleRegex = regexp.MustCompile(`^(.+) workflow run (\w+) for (.+) branch$`)
+
+// CheckSuiteRule marks CI notifications (CheckSuite subjects) as done when they are no longer actionable.
+// GitHub does not provide a subject URL for these notifications, so the workflow and branch
+// are extracted from the notification title.
+type CheckSuiteRule struct {
+	// Succeeded marks the notification as done when a newer run of the same workflow
+	// succeeded on the same branch.
+	Succeeded bool
+	// BranchGone marks the notification as done when the branch was deleted, or merged after the notification.
+	// Merges before the notification are ignored, so that failures on long-lived branches are not cleaned.
+	BranchGone bool
+}
+
+// Name returns the rule name.
+func (r *CheckSuiteRule) Name() string {
+	return "check_suite"
+}
+
+// Evaluate checks the workflow runs and branch related to the notification.
+func (r *CheckSuiteRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	if t.GetSubject().GetType() != TypeCheckSuite {
+		return Skip(), nil
+	}
+
+	matches := checkSuiteTitleRegex.FindStringSubmatch(t.GetSubject().GetTitle())
+	if matches == nil {
+		return Skip(), nil
+	}
+	workflow, branch := matches[1], matches[3]
+	owner, repo := splitRepository(t.GetRepository().GetFullName())
+
+	if r.Succeeded {
+		succeeded, err := r.newerRunSucceeded(ctx, t, owner, repo, workflow, branch)
+		if err != nil {
+			return Skip(), err
+		}
+		if succeeded {
+			return Done(fmt.Sprintf("a newer %s workflow run succeeded on %s branch", workflow, branch)), nil
+		}
+	}
+
+	if r.BranchGone {
+		gone, err := r.branchGone(ctx, t, owner, repo, branch)
+		if err != nil {
+			return Skip(), err
+		}
+		if gone != "" {
+			return Done(fmt.Sprintf("branch %s was %s", branch, gone)), nil
+		}
+	}
+
+	return Skip(), nil
+}
+
+// newerRunSucceeded reports whether a run of the workflow succeeded on the branch after the notification was updated.
+func (r *CheckSuiteRule) newerRunSucceeded(ctx context.Context, t *Thread, owner, repo, workflow, branch string) (bool, error) {
+	since := t.GetUpdatedAt().Time
+	runs, _, err := t.client.Actions.ListRepositoryWorkflowRuns(ctx, owner, repo, &github.ListWorkflowRunsOptions{
+		Branch:      branch,
+		Status:      "success",
+		Created:     ">" + since.UTC().Format(time.RFC3339),
+		ListOptions: github.ListOptions{PerPage: 100},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error listing workflow runs for %s/%s on branch %s: %w", owner, repo, branch, err)
+	}
+
+	for _, run := range runs.WorkflowRuns {
+		if run.GetName() == workflow && run.GetCreatedAt().After(since) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// branchGone returns "deleted" or "merged" when the branch no longer needs attention, or an empty string otherwise.
+// The branch is only considered merged when a pull request from it was merged after the notification was updated.
+func (r *CheckSuiteRule) branchGone(ctx context.Context, t *Thread, owner, repo, branch string) (string, error) {
+	_, resp, err := t.client.Repositories.GetBranch(ctx, owner, repo, branch, 1)
+	if isNotFound(resp) {
+		return "deleted", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching branch %s of %s/%s: %w", branch, owner, repo, err)
+	}
+
+	prs, _, err := t.client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+		State: "closed",
+		Head:  owner + ":" + branch,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error listing pull requests for branch %s of %s/%s: %w", branch, owner, repo, err)
+	}
+
+	since := t.GetUpdatedAt().Time
+	for _, pr := range prs {
+		if pr.MergedAt != nil && pr.MergedAt.After(since) {
+			return "merged", nil
+		}
+	}
+
+	return "", nil
+}
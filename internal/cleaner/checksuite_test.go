@@ -0,0 +1,138 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestCheckSuiteRule(t *testing.T) {
+	updatedAt := time.Now().Add(-time.Hour)
+	newCheckSuiteNotification := func(title string) *github.Notification {
+		return &github.Notification{
+			ID:         github.Ptr("1"),
+			UpdatedAt:  &github.Timestamp{Time: updatedAt},
+			Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+			Subject: &github.NotificationSubject{
+				Title: github.Ptr(title),
+				Type:  github.Ptr(cleaner.TypeCheckSuite),
+			},
+		}
+	}
+
+	t.Run("marks as done when a newer run succeeded", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/actions/runs").
+			MatchParam("branch", "main").
+			MatchParam("status", "success").
+			Reply(200).
+			JSON(map[string]any{
+				"workflow_runs": []map[string]any{
+					{"name": "Lint", "created_at": time.Now()},
+					{"name": "CI", "created_at": time.Now()},
+				},
+			})
+
+		rule := &cleaner.CheckSuiteRule{Succeeded: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), newCheckSuiteNotification("CI workflow run failed for main branch")))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("does not mark as done when only other workflows succeeded", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/actions/runs").
+			Reply(200).
+			JSON(map[string]any{
+				"workflow_runs": []map[string]any{
+					{"name": "Lint", "created_at": time.Now()},
+				},
+			})
+
+		rule := &cleaner.CheckSuiteRule{Succeeded: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), newCheckSuiteNotification("CI workflow run failed for main branch")))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("marks as done when the branch was deleted", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/branches/feature/x").
+			Reply(404).
+			JSON(map[string]string{"message": "Branch not found"})
+
+		rule := &cleaner.CheckSuiteRule{BranchGone: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), newCheckSuiteNotification("CI workflow run failed for feature/x branch")))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.Equal(t, "branch feature/x was deleted", decision.Reason)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("marks as done when the branch was merged", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/branches/feature").
+			Reply(200).
+			JSON(map[string]any{"name": "feature"})
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/pulls").
+			MatchParam("head", "owner:feature").
+			MatchParam("state", "closed").
+			Reply(200).
+			JSON([]map[string]any{{"number": 1, "merged_at": time.Now()}})
+
+		rule := &cleaner.CheckSuiteRule{BranchGone: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), newCheckSuiteNotification("CI workflow run cancelled for feature branch")))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.Equal(t, "branch feature was merged", decision.Reason)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("does not mark as done when the branch was merged before the notification", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/branches/develop").
+			Reply(200).
+			JSON(map[string]any{"name": "develop"})
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/pulls").
+			MatchParam("head", "owner:develop").
+			MatchParam("state", "closed").
+			Reply(200).
+			JSON([]map[string]any{{"number": 1, "merged_at": updatedAt.Add(-24 * time.Hour)}})
+
+		rule := &cleaner.CheckSuiteRule{BranchGone: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), newCheckSuiteNotification("CI workflow run failed for develop branch")))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("skips notifications with unknown titles", func(t *testing.T) {
+		rule := &cleaner.CheckSuiteRule{Succeeded: true, BranchGone: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(nil, newCheckSuiteNotification("Something else")))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+	})
+}
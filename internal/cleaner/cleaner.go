@@ -2,18 +2,28 @@ package cleaner
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
-	"strconv"
+	"slices"
 	"time"
 
 	"github.com/google/go-github/v69/github"
 )
 
 const (
-	TypeIssue            = "Issue"
-	TypePullRequest      = "PullRequest"
+	TypeIssue                            = "Issue"
+	TypePullRequest                      = "PullRequest"
+	TypeRelease                          = "Release"
+	TypeDiscussion                       = "Discussion"
+	TypeCheckSuite                       = "CheckSuite"
+	TypeCommit                           = "Commit"
+	TypeRepositoryVulnerabilityAlert     = "RepositoryVulnerabilityAlert"
+	TypeRepositoryDependabotAlertsThread = "RepositoryDependabotAlertsThread"
+
 	DefaultDaysThreshold = 30
+	DefaultOlderThan     = DefaultDaysThreshold * 24 * time.Hour
 )
 
 // Cleaner defines the interface for cleaning notifications.
@@ -23,9 +33,29 @@ type Cleaner interface {
 
 // NotificationsCleaner defines the cleaner struct.
 type NotificationsCleaner struct {
-	GitHubClient  *github.Client
-	OlderThanDays int
-	DryRun        bool
+	GitHubClient *github.Client
+	// OlderThan is the age threshold of the default rules.
+	OlderThan time.Duration
+	DryRun    bool
+	// Rules are evaluated in order for each notification.
+	// When nil, the DefaultRules are used.
+	Rules []Rule
+	// Filter restricts which notifications are processed.
+	Filter Filter
+	// Recorder records the subscription changes, so that they can be reverted.
+	// When nil, the changes are not recorded.
+	Recorder Recorder
+	// RunID identifies the recorded changes of each run. When empty, a unique ID is generated
+	// for each run from its start time.
+	RunID string
+	// NoisyRepos configures the detection of the repositories where many notifications are cleaned.
+	NoisyRepos NoisyRepos
+	// BatchRead marks the threads with the read action as read with repository-wide calls,
+	// instead of one call per thread, at the end of the run.
+	BatchRead bool
+
+	// runID identifies the current run.
+	runID string
 }
 
 // Option defines a functional option for NotificationsCleaner.
@@ -35,9 +65,9 @@ type Option func(*NotificationsCleaner)
 // with the provided options. It initializes with a default GitHubClient.
 func NewNotificationsCleaner(opts ...Option) *NotificationsCleaner {
 	nc := &NotificationsCleaner{
-		GitHubClient:  github.NewClient(nil),
-		OlderThanDays: DefaultDaysThreshold,
-		DryRun:        false,
+		GitHubClient: github.NewClient(nil),
+		OlderThan:    DefaultOlderThan,
+		DryRun:       false,
 	}
 
 	for _, opt := range opts {
@@ -57,8 +87,49 @@ func WithGitHubClient(client *github.Client) Option {
 // WithOlderThanDays is an option to set the age threshold (in days)
 // for cleaning notifications.
 func WithOlderThanDays(days int) Option {
+	return WithOlderThan(time.Duration(days) * 24 * time.Hour)
+}
+
+// WithOlderThan is an option to set the age threshold for cleaning notifications.
+func WithOlderThan(olderThan time.Duration) Option {
+	return func(nc *NotificationsCleaner) {
+		nc.OlderThan = olderThan
+	}
+}
+
+// WithRules is an option to set the rules used to decide which notifications
+// should be cleaned. Rules are evaluated in the given order.
+func WithRules(rules ...Rule) Option {
+	return func(nc *NotificationsCleaner) {
+		nc.Rules = rules
+	}
+}
+
+// WithFilter is an option to restrict which notifications are processed.
+func WithFilter(filter Filter) Option {
+	return func(nc *NotificationsCleaner) {
+		nc.Filter = filter
+	}
+}
+
+// WithRecorder is an option to record the subscription changes made by the cleaner.
+func WithRecorder(recorder Recorder) Option {
+	return func(nc *NotificationsCleaner) {
+		nc.Recorder = recorder
+	}
+}
+
+// WithNoisyRepos is an option to report, or mute, the repositories where many notifications are cleaned.
+func WithNoisyRepos(noisyRepos NoisyRepos) Option {
+	return func(nc *NotificationsCleaner) {
+		nc.NoisyRepos = noisyRepos
+	}
+}
+
+// WithBatchRead is an option to mark threads as read in batches.
+func WithBatchRead(batchRead bool) Option {
 	return func(nc *NotificationsCleaner) {
-		nc.OlderThanDays = days
+		nc.BatchRead = batchRead
 	}
 }
 
@@ -70,11 +141,75 @@ func WithDryRun(dryRun bool) Option {
 }
 
 // Clean performs cleaning notifications.
-// It marks notifications as done if they are related to closed pull requests/issues
-// or if they are older than the configured number of days.
+// It marks notifications as done (or applies the actions of the rule) when one of the configured rules matches.
 func (nc *NotificationsCleaner) Clean(ctx context.Context) error {
-	threshold := time.Now().AddDate(0, 0, -nc.OlderThanDays)
+	listedAt := time.Now()
+	nc.runID = nc.RunID
+	if nc.runID == "" {
+		nc.runID = newRunID(listedAt)
+	}
+	slog.Info("starting run", slog.String("run_id", nc.runID))
 
+	allNotications, err := nc.listNotifications(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := nc.rules()
+	viewer := NewViewer(nc.GitHubClient)
+	counts := make(map[Outcome]int)
+	cleanedByRepo := make(map[string]int)
+	filtered := 0
+	var batch *readBatch
+	if nc.BatchRead && !nc.DryRun {
+		batch = newReadBatch()
+	}
+	for _, n := range allNotications {
+		if !nc.Filter.Match(n) {
+			slog.Debug("skipping notification excluded by filters",
+				slog.String("id", n.GetID()),
+				slog.String("repository", n.GetRepository().GetFullName()),
+			)
+			filtered++
+			continue
+		}
+		t := NewThread(nc.GitHubClient, n, WithThreadViewer(viewer))
+		outcome := nc.processNotification(ctx, t, rules, batch)
+		counts[outcome]++
+		if outcome == OutcomeDone {
+			cleanedByRepo[n.GetRepository().GetFullName()]++
+		}
+	}
+
+	if batch != nil {
+		nc.flushReadBatch(ctx, batch, allNotications, listedAt)
+	}
+
+	slog.Info("notifications processed",
+		slog.Int("total", len(allNotications)),
+		slog.Int("filtered", filtered),
+		slog.Int("done", counts[OutcomeDone]),
+		slog.Int("kept", counts[OutcomeKeep]),
+		slog.Int("unmatched", counts[OutcomeSkip]),
+	)
+
+	if err := nc.handleNoisyRepos(ctx, cleanedByRepo); err != nil {
+		return fmt.Errorf("error checking noisy repositories: %w", err)
+	}
+
+	return nil
+}
+
+// newRunID generates a run ID from the start time of the run, for example "20250101T120000Z-1a2b3c4d".
+// The random suffix keeps the IDs of runs started in the same second unique.
+func newRunID(start time.Time) string {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return start.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+}
+
+// listNotifications fetches all the notifications of the authenticated user.
+func (nc *NotificationsCleaner) listNotifications(ctx context.Context) ([]*github.Notification, error) {
 	opts := &github.NotificationListOptions{
 		All: true,
 		ListOptions: github.ListOptions{
@@ -90,106 +225,163 @@ func (nc *NotificationsCleaner) Clean(ctx context.Context) error {
 		)
 		notifications, resp, err := nc.GitHubClient.Activity.ListNotifications(ctx, opts)
 		if err != nil {
-			return fmt.Errorf("error listing notifications: %w", err)
+			return nil, fmt.Errorf("error listing notifications: %w", err)
 		}
 
 		allNotications = append(allNotications, notifications...)
 
 		if resp.NextPage == 0 {
-			break
+			return allNotications, nil
 		}
 		opts.Page = resp.NextPage
 	}
-
-	for _, n := range allNotications {
-		nc.processNotification(ctx, n, threshold)
-	}
-
-	return nil
 }
 
-// processNotification processes a single notification.
-func (nc *NotificationsCleaner) processNotification(ctx context.Context, n *github.Notification, threshold time.Time) {
-	markDone, err := nc.canBeMarkedAsDone(ctx, n, threshold)
+// processNotification processes a single notification and returns the outcome of the rules.
+// Notifications that could not be evaluated are reported as OutcomeSkip.
+func (nc *NotificationsCleaner) processNotification(ctx context.Context, n *Thread, rules []Rule, batch *readBatch) Outcome {
+	rule, decision, err := evaluateRules(ctx, n, rules)
 	if err != nil {
 		slog.Error("error checking notification",
 			slog.String("notification_id", n.GetID()),
 			slog.String("error", err.Error()),
 		)
-		return
+		return OutcomeSkip
 	}
 
-	if !markDone {
-		return
-	}
-
-	slog.Info("marking notification as done",
+	attrs := []any{
 		slog.String("id", n.GetID()),
-		slog.String(("repository"), n.GetRepository().GetFullName()),
+		slog.String("repository", n.GetRepository().GetFullName()),
 		slog.String("subject", n.GetSubject().GetTitle()),
-	)
+	}
+	if rule != nil {
+		attrs = append(attrs,
+			slog.String("rule", rule.Name()),
+			slog.String("reason", decision.Reason),
+		)
+	}
+	for _, attr := range decision.Attrs {
+		attrs = append(attrs, attr)
+	}
+
+	actions := decision.Actions
+	if len(actions) == 0 {
+		actions = []Action{ActionDone}
+	}
+
+	switch decision.Outcome {
+	case OutcomeKeep:
+		slog.Info("keeping notification", attrs...)
+		return decision.Outcome
+	case OutcomeDone:
+		slog.Info("cleaning notification", append(attrs, slog.Any("actions", actions))...)
+	default:
+		slog.Debug("no rule matched notification", attrs...)
+		return decision.Outcome
+	}
 
 	if nc.DryRun {
-		slog.Debug("dry-run mode enabled. Skipping marking notification as done.")
-		return
+		slog.Debug("dry-run mode enabled. Skipping notification actions.")
+		return decision.Outcome
 	}
 
-	nID, err := strconv.Atoi(n.GetID())
+	nc.applyActions(ctx, n, actions, batch)
+	return decision.Outcome
+}
+
+// applyActions applies the actions to the thread, logging errors.
+// When batch is not nil, the read action is deferred to the batch, unless the thread is also marked as done.
+func (nc *NotificationsCleaner) applyActions(ctx context.Context, n *Thread, actions []Action, batch *readBatch) {
+	// Actions are applied in a fixed order, so that the subscription is changed
+	// before the thread is marked as done.
+	for _, action := range Actions {
+		if !slices.Contains(actions, action) {
+			continue
+		}
+		if action == ActionRead && batch != nil && !slices.Contains(actions, ActionDone) {
+			batch.add(n)
+			continue
+		}
+		if err := nc.applyThreadAction(ctx, n, action); err != nil {
+			// Log error but continue with the other actions and notifications.
+			slog.Error("error applying action to notification",
+				slog.String("notification_id", n.GetID()),
+				slog.String("action", string(action)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// applyThreadAction applies a single action to the thread.
+// Subscription changes are recorded, along with the previous subscription, when a Recorder is set.
+// They are recorded before being made, and skipped when they cannot be recorded, so that every change
+// can be reverted.
+func (nc *NotificationsCleaner) applyThreadAction(ctx context.Context, n *Thread, action Action) error {
+	if (action != ActionUnsubscribe && action != ActionIgnore) || nc.Recorder == nil {
+		return applyAction(ctx, nc.GitHubClient, n.GetID(), action)
+	}
+
+	// The subscription is not changed when its current state is unknown,
+	// as the change could not be reverted.
+	previous, err := threadSubscription(ctx, nc.GitHubClient, n.GetID())
 	if err != nil {
-		slog.Error("error converting notification ID to int",
-			slog.String("notification_id", n.GetID()),
-			slog.String("error", err.Error()),
-		)
-		return
+		return err
 	}
 
-	_, err = nc.GitHubClient.Activity.MarkThreadDone(ctx, int64(nID))
+	err = nc.recordChange(SubscriptionChange{
+		ThreadID:   n.GetID(),
+		Repository: n.GetRepository().GetFullName(),
+		Subject:    n.GetSubject().GetTitle(),
+		Action:     action,
+		Previous:   previous,
+	})
 	if err != nil {
-		// Log error but continue processing other notifications.
-		slog.Error("error marking notification as done",
-			slog.String("notification_id", n.GetID()),
-			slog.String("error", err.Error()),
-		)
+		return err
+	}
+
+	return applyAction(ctx, nc.GitHubClient, n.GetID(), action)
+}
+
+// recordChange records a subscription change of the current run.
+// Changes are recorded before being made: when a change then fails, restoring it
+// sets the subscription back to its unchanged state, which is harmless.
+func (nc *NotificationsCleaner) recordChange(change SubscriptionChange) error {
+	change.Time = time.Now()
+	change.RunID = nc.runID
+	if err := nc.Recorder.Record(change); err != nil {
+		return fmt.Errorf("error recording subscription change, skipping it: %w", err)
 	}
+	return nil
 }
 
-// canBeMarkedAsDone checks if a notification should be marked as done
-// nolint: gocyclo
-func (nc *NotificationsCleaner) canBeMarkedAsDone(ctx context.Context, n *github.Notification, threshold time.Time) (bool, error) {
-	// Rule 1: Check notiications older than the threshold
-	if n.UpdatedAt != nil && n.UpdatedAt.Time.Before(threshold) {
-		return true, nil
+// rules returns the configured rules, falling back to the default ones.
+func (nc *NotificationsCleaner) rules() []Rule {
+	if nc.Rules != nil {
+		return nc.Rules
 	}
+	return DefaultRules(nc.OlderThan)
+}
 
-	// Rule 2: Check if the notification is related to a closed issue or pull request
-	subjectType := n.GetSubject().GetType()
-	if subjectType == TypeIssue || subjectType == TypePullRequest {
-		owner, repo, number, err := parseNotificationURL(n.GetSubject().GetURL())
+// evaluateRules evaluates the rules in order and returns the first rule
+// with a decision other than OutcomeSkip, along with its decision.
+// The attributes of the skipped decisions are kept in the returned decision,
+// so that the report shows, for example, the threshold applied to a notification
+// that was not cleaned.
+func evaluateRules(ctx context.Context, t *Thread, rules []Rule) (Rule, Decision, error) {
+	var attrs []slog.Attr
+	for _, rule := range rules {
+		decision, err := rule.Evaluate(ctx, t)
 		if err != nil {
-			return false, fmt.Errorf("error parsing notification URL for notification %s: %w", n.GetID(), err)
+			return rule, Skip(), fmt.Errorf("error evaluating rule %s: %w", rule.Name(), err)
 		}
 
-		switch subjectType {
-		case TypePullRequest:
-			pr, _, err := nc.GitHubClient.PullRequests.Get(ctx, owner, repo, number)
-			if err != nil {
-				return false, fmt.Errorf("error fetching pull request %s/%s#%d: %w", owner, repo, number, err)
-			}
-
-			if pr.GetState() == "closed" {
-				return true, nil
-			}
-		case TypeIssue:
-			issue, _, err := nc.GitHubClient.Issues.Get(ctx, owner, repo, number)
-			if err != nil {
-				return false, fmt.Errorf("error fetching issue %s/%s#%d: %w", owner, repo, number, err)
-			}
-
-			if issue.GetState() == "closed" {
-				return true, nil
-			}
+		if decision.Outcome != OutcomeSkip {
+			decision.Attrs = append(attrs, decision.Attrs...)
+			return rule, decision, nil
 		}
+		attrs = append(attrs, decision.Attrs...)
 	}
 
-	return false, nil
+	return nil, Decision{Outcome: OutcomeSkip, Attrs: attrs}, nil
 }
@@ -20,11 +20,27 @@ func setupMockClient(t *testing.T) *github.Client {
 	return github.NewClient(httpClient)
 }
 
+// stubRule is a Rule that always returns the same decision.
+type stubRule struct {
+	name     string
+	decision cleaner.Decision
+	calls    int
+}
+
+func (r *stubRule) Name() string {
+	return r.name
+}
+
+func (r *stubRule) Evaluate(_ context.Context, _ *cleaner.Thread) (cleaner.Decision, error) {
+	r.calls++
+	return r.decision, nil
+}
+
 func TestNewNotificationsCleaner(t *testing.T) {
 	t.Run("default initialization sets expected values", func(t *testing.T) {
 		nc := cleaner.NewNotificationsCleaner()
 		assert.NotNil(t, nc.GitHubClient, "expected default GitHubClient to be non-nil")
-		assert.Equal(t, cleaner.DefaultDaysThreshold, nc.OlderThanDays, "expected default OlderThanDays value")
+		assert.Equal(t, cleaner.DefaultOlderThan, nc.OlderThan, "expected default OlderThan value")
 		assert.False(t, nc.DryRun, "expected default DryRun to be false")
 	})
 
@@ -39,13 +55,24 @@ func TestNewNotificationsCleaner(t *testing.T) {
 	t.Run("WithOlderThanDays option sets the threshold", func(t *testing.T) {
 		customDays := 30
 		nc := cleaner.NewNotificationsCleaner(cleaner.WithOlderThanDays(customDays))
-		assert.Equal(t, customDays, nc.OlderThanDays, "expected OlderThanDays to be set to custom value")
+		assert.Equal(t, 30*24*time.Hour, nc.OlderThan, "expected OlderThan to be set to custom value")
+	})
+
+	t.Run("WithOlderThan option sets the threshold", func(t *testing.T) {
+		nc := cleaner.NewNotificationsCleaner(cleaner.WithOlderThan(36 * time.Hour))
+		assert.Equal(t, 36*time.Hour, nc.OlderThan, "expected OlderThan to be set to custom value")
 	})
 
 	t.Run("WithDryRun option enables dry-run mode", func(t *testing.T) {
 		nc := cleaner.NewNotificationsCleaner(cleaner.WithDryRun(true))
 		assert.True(t, nc.DryRun, "expected DryRun to be enabled")
 	})
+
+	t.Run("WithRules option sets the rules", func(t *testing.T) {
+		rule := &stubRule{name: "stub"}
+		nc := cleaner.NewNotificationsCleaner(cleaner.WithRules(rule))
+		assert.Equal(t, []cleaner.Rule{rule}, nc.Rules, "expected Rules to be set to custom rules")
+	})
 }
 
 func TestClean(t *testing.T) {
@@ -177,6 +204,41 @@ func TestClean(t *testing.T) {
 			assert.True(t, gock.IsDone())
 		})
 
+		t.Run("does not mark old notifications of open security alerts as done", func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/notifications").
+				Reply(200).
+				JSON([]*github.Notification{
+					{
+						ID:         github.Ptr("1"),
+						UpdatedAt:  &github.Timestamp{Time: time.Now().AddDate(0, 0, -60)},
+						Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+						Subject: &github.NotificationSubject{
+							Title: github.Ptr("Potential security vulnerability found in the lodash dependency"),
+							Type:  github.Ptr(cleaner.TypeRepositoryVulnerabilityAlert),
+						},
+					},
+				})
+
+			gock.New("https://api.github.com").
+				Get("/repos/owner/repo/dependabot/alerts").
+				Reply(200).
+				JSON([]map[string]any{{"number": 1, "state": "open"}})
+
+			// No MarkThreadDone call expected, as the alert is still open.
+
+			nc := cleaner.NewNotificationsCleaner(
+				cleaner.WithGitHubClient(setupMockClient(t)),
+				cleaner.WithOlderThanDays(15),
+			)
+
+			err := nc.Clean(context.Background())
+			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
+		})
+
 		t.Run("does not mark open issue notifications as done", func(t *testing.T) {
 			defer gock.Off()
 
@@ -218,6 +280,145 @@ func TestClean(t *testing.T) {
 		})
 	})
 
+	t.Run("custom rules", func(t *testing.T) {
+		t.Run("stops at the first matching rule", func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/notifications").
+				Reply(200).
+				JSON([]*github.Notification{
+					{
+						ID:        github.Ptr("1"),
+						UpdatedAt: &github.Timestamp{Time: time.Now()},
+					},
+				})
+
+			gock.New("https://api.github.com").
+				Delete("/notifications/threads/1").
+				Reply(204)
+
+			skipRule := &stubRule{name: "skip", decision: cleaner.Skip()}
+			doneRule := &stubRule{name: "done", decision: cleaner.Done("stub")}
+			lastRule := &stubRule{name: "last", decision: cleaner.Done("stub")}
+
+			nc := cleaner.NewNotificationsCleaner(
+				cleaner.WithGitHubClient(setupMockClient(t)),
+				cleaner.WithRules(skipRule, doneRule, lastRule),
+			)
+
+			err := nc.Clean(context.Background())
+			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
+			assert.Equal(t, 1, skipRule.calls)
+			assert.Equal(t, 1, doneRule.calls)
+			assert.Equal(t, 0, lastRule.calls, "expected rules after the first match to be skipped")
+		})
+
+		t.Run("keeps notifications matched by a keep rule", func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/notifications").
+				Reply(200).
+				JSON([]*github.Notification{
+					{
+						ID:        github.Ptr("1"),
+						Reason:    github.Ptr(cleaner.ReasonMention),
+						UpdatedAt: &github.Timestamp{Time: time.Now().AddDate(0, 0, -60)},
+					},
+				})
+
+			// No MarkThreadDone call expected, as the keep rule matches before the age rule.
+
+			ageRule := &stubRule{name: "age", decision: cleaner.Done("stub")}
+			nc := cleaner.NewNotificationsCleaner(
+				cleaner.WithGitHubClient(setupMockClient(t)),
+				cleaner.WithRules(
+					cleaner.NewReasonRule([]string{cleaner.ReasonMention}, 0, cleaner.OutcomeKeep),
+					ageRule,
+				),
+			)
+
+			err := nc.Clean(context.Background())
+			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
+			assert.Equal(t, 0, ageRule.calls)
+		})
+
+		t.Run("replaces the default rules", func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/notifications").
+				Reply(200).
+				JSON([]*github.Notification{
+					{
+						ID:        github.Ptr("1"),
+						UpdatedAt: &github.Timestamp{Time: time.Now().AddDate(0, 0, -60)},
+					},
+				})
+
+			// No MarkThreadDone call expected, as the age rule is not configured.
+
+			nc := cleaner.NewNotificationsCleaner(
+				cleaner.WithGitHubClient(setupMockClient(t)),
+				cleaner.WithRules(&stubRule{name: "skip", decision: cleaner.Skip()}),
+			)
+
+			err := nc.Clean(context.Background())
+			require.NoError(t, err)
+			assert.True(t, gock.IsDone())
+		})
+	})
+
+	t.Run("skips notifications excluded by filters", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/notifications").
+			Reply(200).
+			JSON([]*github.Notification{
+				{
+					ID:         github.Ptr("1"),
+					UpdatedAt:  &github.Timestamp{Time: time.Now().AddDate(0, 0, -20)},
+					Repository: &github.Repository{FullName: github.Ptr("owner/excluded")},
+				},
+				{
+					// Excluded notifications must not trigger any subject lookup.
+					ID:         github.Ptr("3"),
+					UpdatedAt:  &github.Timestamp{Time: time.Now()},
+					Repository: &github.Repository{FullName: github.Ptr("other/repo")},
+					Subject: &github.NotificationSubject{
+						Type: github.Ptr(cleaner.TypePullRequest),
+						URL:  github.Ptr("https://api.github.com/repos/other/repo/pulls/1"),
+					},
+				},
+				{
+					ID:         github.Ptr("2"),
+					UpdatedAt:  &github.Timestamp{Time: time.Now().AddDate(0, 0, -20)},
+					Repository: &github.Repository{FullName: github.Ptr("owner/included")},
+				},
+			})
+
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/2").
+			Reply(204)
+
+		nc := cleaner.NewNotificationsCleaner(
+			cleaner.WithGitHubClient(setupMockClient(t)),
+			cleaner.WithOlderThanDays(15),
+			cleaner.WithFilter(cleaner.Filter{
+				Repos:        []string{"owner/*"},
+				ExcludeRepos: []string{"owner/excluded"},
+			}),
+		)
+
+		err := nc.Clean(context.Background())
+		require.NoError(t, err)
+		assert.True(t, gock.IsDone())
+	})
+
 	t.Run("respects dry-run mode", func(t *testing.T) {
 		defer gock.Off()
 
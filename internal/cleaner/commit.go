@@ -0,0 +1,100 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// CommitRule marks commit comment notifications (Commit subjects) as done.
+// A commit matches when all the enabled conditions are true.
+type CommitRule struct {
+	// MaxAge restricts the rule to commits authored longer than MaxAge ago. Disabled when zero.
+	MaxAge time.Duration
+	// OnDefaultBranch restricts the rule to commits reachable from the repository default branch.
+	OnDefaultBranch bool
+}
+
+// Name returns the rule name.
+func (r *CommitRule) Name() string {
+	return "commit"
+}
+
+// Evaluate checks the commit related to the notification.
+func (r *CommitRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	if t.GetSubject().GetType() != TypeCommit {
+		return Skip(), nil
+	}
+
+	ref, err := parseSubjectURL(t.GetSubject().GetURL())
+	if err != nil {
+		return Skip(), fmt.Errorf("error parsing notification URL for notification %s: %w", t.GetID(), err)
+	}
+
+	if r.MaxAge > 0 {
+		old, err := r.isOld(ctx, t, ref)
+		if err != nil {
+			return Skip(), err
+		}
+		if !old {
+			return Skip(), nil
+		}
+	}
+
+	if r.OnDefaultBranch {
+		onDefault, err := r.onDefaultBranch(ctx, t, ref)
+		if err != nil {
+			return Skip(), err
+		}
+		if !onDefault {
+			return Skip(), nil
+		}
+	}
+
+	if r.MaxAge > 0 {
+		return Done(fmt.Sprintf("commit %s is older than %s", shortSHA(ref.ID), r.MaxAge)), nil
+	}
+	return Done(fmt.Sprintf("commit %s is on the default branch", shortSHA(ref.ID))), nil
+}
+
+// isOld reports whether the commit was committed longer than MaxAge ago.
+func (r *CommitRule) isOld(ctx context.Context, t *Thread, ref subjectRef) (bool, error) {
+	commit, _, err := t.client.Repositories.GetCommit(ctx, ref.Owner, ref.Repo, ref.ID, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return false, fmt.Errorf("error fetching commit %s/%s@%s: %w", ref.Owner, ref.Repo, ref.ID, err)
+	}
+
+	date := commit.GetCommit().GetCommitter().GetDate()
+	return !date.IsZero() && date.Before(time.Now().Add(-r.MaxAge)), nil
+}
+
+// onDefaultBranch reports whether the commit is reachable from the repository default branch.
+func (r *CommitRule) onDefaultBranch(ctx context.Context, t *Thread, ref subjectRef) (bool, error) {
+	repo, _, err := t.client.Repositories.Get(ctx, ref.Owner, ref.Repo)
+	if err != nil {
+		return false, fmt.Errorf("error fetching repository %s/%s: %w", ref.Owner, ref.Repo, err)
+	}
+
+	comparison, _, err := t.client.Repositories.CompareCommits(ctx, ref.Owner, ref.Repo, repo.GetDefaultBranch(), ref.ID, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return false, fmt.Errorf("error comparing commit %s/%s@%s with the default branch: %w", ref.Owner, ref.Repo, ref.ID, err)
+	}
+
+	// The commit is on the default branch when it is behind or identical to its head.
+	switch comparison.GetStatus() {
+	case "behind", "identical":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// shortSHA returns the abbreviated form of a commit SHA.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
@@ -0,0 +1,113 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestCommitRule(t *testing.T) {
+	const sha = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+	commitNotification := &github.Notification{
+		ID: github.Ptr("1"),
+		Subject: &github.NotificationSubject{
+			Type: github.Ptr(cleaner.TypeCommit),
+			URL:  github.Ptr("https://api.github.com/repos/owner/repo/commits/" + sha),
+		},
+	}
+
+	mockCommit := func(date time.Time) {
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/commits/" + sha).
+			Reply(200).
+			JSON(map[string]any{
+				"sha":    sha,
+				"commit": map[string]any{"committer": map[string]any{"date": date}},
+			})
+	}
+
+	mockComparison := func(status string) {
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo").
+			Reply(200).
+			JSON(map[string]any{"default_branch": "main"})
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/compare/main..." + sha).
+			Reply(200).
+			JSON(map[string]any{"status": status})
+	}
+
+	t.Run("marks old commits on the default branch as done", func(t *testing.T) {
+		defer gock.Off()
+
+		mockCommit(time.Now().AddDate(0, 0, -10))
+		mockComparison("behind")
+
+		rule := &cleaner.CommitRule{MaxAge: 7 * 24 * time.Hour, OnDefaultBranch: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), commitNotification))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.Equal(t, "commit 4b825dc is older than 168h0m0s", decision.Reason)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("does not mark recent commits as done", func(t *testing.T) {
+		defer gock.Off()
+
+		mockCommit(time.Now())
+
+		rule := &cleaner.CommitRule{MaxAge: 7 * 24 * time.Hour, OnDefaultBranch: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), commitNotification))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("does not mark commits outside the default branch as done", func(t *testing.T) {
+		defer gock.Off()
+
+		mockComparison("diverged")
+
+		rule := &cleaner.CommitRule{OnDefaultBranch: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), commitNotification))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestClean_CommitNotifications(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Get("/notifications").
+		Reply(200).
+		JSON([]*github.Notification{
+			{
+				ID:        github.Ptr("1"),
+				UpdatedAt: &github.Timestamp{Time: time.Now()},
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypeCommit),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/commits/4b825dc642cb6eb9a060e54bf8d69288fbee4904"),
+				},
+			},
+		})
+
+	// Commit notifications must not trigger any issue or pull request lookup.
+
+	nc := cleaner.NewNotificationsCleaner(
+		cleaner.WithGitHubClient(setupMockClient(t)),
+	)
+
+	err := nc.Clean(context.Background())
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
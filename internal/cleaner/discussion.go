@@ -0,0 +1,161 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Discussion holds the details of a GitHub discussion.
+// Discussions are not available in the REST API, so they are fetched with GraphQL.
+type Discussion struct {
+	Number      int       `json:"number"`
+	Title       string    `json:"title"`
+	Closed      bool      `json:"closed"`
+	Locked      bool      `json:"locked"`
+	IsAnswered  bool      `json:"isAnswered"`
+	StateReason string    `json:"stateReason"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+const discussionFields = `number title closed locked isAnswered stateReason updatedAt`
+
+const discussionByNumberQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    discussion(number: $number) { ` + discussionFields + ` }
+  }
+}`
+
+const discussionSearchQuery = `query($query: String!) {
+  search(query: $query, type: DISCUSSION, first: 10) {
+    nodes { ... on Discussion { ` + discussionFields + ` } }
+  }
+}`
+
+// Discussion returns the discussion related to the notification.
+// GitHub usually does not provide a subject URL for discussion notifications,
+// in which case the discussion is searched by title in the notification repository.
+func (t *Thread) Discussion(ctx context.Context) (*Discussion, error) {
+	if t.discussion != nil {
+		return t.discussion, nil
+	}
+
+	var (
+		discussion *Discussion
+		err        error
+	)
+	if owner, repo, number, parseErr := parseNotificationURL(t.GetSubject().GetURL()); parseErr == nil {
+		discussion, err = t.discussionByNumber(ctx, owner, repo, number)
+	} else {
+		discussion, err = t.discussionByTitle(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	t.discussion = discussion
+	return discussion, nil
+}
+
+// discussionByNumber fetches a discussion by its number.
+func (t *Thread) discussionByNumber(ctx context.Context, owner, repo string, number int) (*Discussion, error) {
+	var data struct {
+		Repository struct {
+			Discussion *Discussion `json:"discussion"`
+		} `json:"repository"`
+	}
+
+	vars := map[string]any{"owner": owner, "name": repo, "number": number}
+	if err := queryGraphQL(ctx, t.client, discussionByNumberQuery, vars, &data); err != nil {
+		return nil, fmt.Errorf("error fetching discussion %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	if data.Repository.Discussion == nil {
+		return nil, fmt.Errorf("discussion %s/%s#%d not found", owner, repo, number)
+	}
+
+	return data.Repository.Discussion, nil
+}
+
+// discussionByTitle searches a discussion with the notification title in the notification repository.
+func (t *Thread) discussionByTitle(ctx context.Context) (*Discussion, error) {
+	repo := t.GetRepository().GetFullName()
+	title := t.GetSubject().GetTitle()
+
+	var data struct {
+		Search struct {
+			Nodes []*Discussion `json:"nodes"`
+		} `json:"search"`
+	}
+
+	query := fmt.Sprintf("repo:%s in:title %s", repo, searchPhrase(title))
+	if err := queryGraphQL(ctx, t.client, discussionSearchQuery, map[string]any{"query": query}, &data); err != nil {
+		return nil, fmt.Errorf("error searching discussion %q in %s: %w", title, repo, err)
+	}
+
+	var matches []*Discussion
+	for _, d := range data.Search.Nodes {
+		if d != nil && strings.EqualFold(d.Title, title) {
+			matches = append(matches, d)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("discussion %q not found in %s", title, repo)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("%d discussions titled %q found in %s", len(matches), title, repo)
+	}
+}
+
+// searchPhrase quotes the title as a phrase of the GitHub search syntax.
+// The search syntax has no escaping, so quotes and backslashes are replaced by spaces,
+// which the search ignores like other punctuation.
+func searchPhrase(title string) string {
+	return `"` + strings.NewReplacer(`"`, " ", `\`, " ").Replace(title) + `"`
+}
+
+// DiscussionRule marks discussion notifications as done based on the discussion state.
+// A discussion matches when any of the enabled conditions is true.
+type DiscussionRule struct {
+	// Closed marks closed discussions as done.
+	Closed bool
+	// Locked marks locked discussions as done.
+	Locked bool
+	// Answered marks answered discussions as done.
+	Answered bool
+}
+
+// Name returns the rule name.
+func (r *DiscussionRule) Name() string {
+	return "discussion"
+}
+
+// Evaluate checks the discussion related to the notification.
+func (r *DiscussionRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	if t.GetSubject().GetType() != TypeDiscussion {
+		return Skip(), nil
+	}
+
+	d, err := t.Discussion(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+
+	switch {
+	case r.Closed && d.Closed:
+		if d.StateReason == "" {
+			return Done("discussion is closed"), nil
+		}
+		return Done(fmt.Sprintf("discussion is closed (%s)", strings.ToLower(d.StateReason))), nil
+	case r.Locked && d.Locked:
+		return Done("discussion is locked"), nil
+	case r.Answered && d.IsAnswered:
+		return Done("discussion is answered"), nil
+	}
+
+	return Skip(), nil
+}
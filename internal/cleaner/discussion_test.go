@@ -0,0 +1,184 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestDiscussionRule(t *testing.T) {
+	t.Run("resolves discussion by number", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			MatchType("json").
+			BodyString(`"number":7`).
+			Reply(200).
+			JSON(map[string]any{
+				"data": map[string]any{
+					"repository": map[string]any{
+						"discussion": map[string]any{"number": 7, "closed": true, "stateReason": "RESOLVED"},
+					},
+				},
+			})
+
+		n := &github.Notification{
+			ID: github.Ptr("1"),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypeDiscussion),
+				URL:  github.Ptr("https://api.github.com/repos/owner/repo/discussions/7"),
+			},
+		}
+
+		rule := &cleaner.DiscussionRule{Closed: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.Equal(t, "discussion is closed (resolved)", decision.Reason)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("searches discussion by title without subject URL", func(t *testing.T) {
+		testCases := []struct {
+			name       string
+			rule       *cleaner.DiscussionRule
+			discussion map[string]any
+			expected   cleaner.Outcome
+		}{
+			{"answered discussion", &cleaner.DiscussionRule{Answered: true}, map[string]any{"isAnswered": true}, cleaner.OutcomeDone},
+			{"locked discussion", &cleaner.DiscussionRule{Locked: true}, map[string]any{"locked": true}, cleaner.OutcomeDone},
+			{"open discussion", &cleaner.DiscussionRule{Closed: true, Locked: true, Answered: true}, map[string]any{}, cleaner.OutcomeSkip},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				defer gock.Off()
+
+				tc.discussion["title"] = "How to configure?"
+				gock.New("https://api.github.com").
+					Post("/graphql").
+					Reply(200).
+					JSON(map[string]any{
+						"data": map[string]any{
+							"search": map[string]any{
+								"nodes": []any{
+									map[string]any{"title": "Other discussion", "closed": true, "locked": true, "isAnswered": true},
+									tc.discussion,
+								},
+							},
+						},
+					})
+
+				n := &github.Notification{
+					ID:         github.Ptr("1"),
+					Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+					Subject: &github.NotificationSubject{
+						Title: github.Ptr("How to configure?"),
+						Type:  github.Ptr(cleaner.TypeDiscussion),
+					},
+				}
+
+				decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, decision.Outcome)
+				assert.True(t, gock.IsDone())
+			})
+		}
+	})
+
+	t.Run("searches titles with quotes as a plain phrase", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			BodyString(`repo:owner/repo in:title \\"Error  C: foo \\"`).
+			Reply(200).
+			JSON(map[string]any{
+				"data": map[string]any{
+					"search": map[string]any{
+						"nodes": []any{
+							map[string]any{"title": `Error "C:\foo"`, "closed": true},
+						},
+					},
+				},
+			})
+
+		n := &github.Notification{
+			ID:         github.Ptr("1"),
+			Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+			Subject: &github.NotificationSubject{
+				Title: github.Ptr(`Error "C:\foo"`),
+				Type:  github.Ptr(cleaner.TypeDiscussion),
+			},
+		}
+
+		rule := &cleaner.DiscussionRule{Closed: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("returns error when several discussions have the title", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			Reply(200).
+			JSON(map[string]any{
+				"data": map[string]any{
+					"search": map[string]any{
+						"nodes": []any{
+							map[string]any{"number": 1, "title": "Question", "closed": true},
+							map[string]any{"number": 2, "title": "Question"},
+						},
+					},
+				},
+			})
+
+		n := &github.Notification{
+			ID:         github.Ptr("1"),
+			Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+			Subject: &github.NotificationSubject{
+				Title: github.Ptr("Question"),
+				Type:  github.Ptr(cleaner.TypeDiscussion),
+			},
+		}
+
+		rule := &cleaner.DiscussionRule{Closed: true}
+		_, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "2 discussions")
+	})
+
+	t.Run("returns GraphQL errors", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Post("/graphql").
+			Reply(200).
+			JSON(map[string]any{
+				"errors": []map[string]any{{"message": "Could not resolve to a Repository"}},
+			})
+
+		n := &github.Notification{
+			ID: github.Ptr("1"),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypeDiscussion),
+				URL:  github.Ptr("https://api.github.com/repos/owner/repo/discussions/7"),
+			},
+		}
+
+		rule := &cleaner.DiscussionRule{Closed: true}
+		_, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Could not resolve to a Repository")
+	})
+}
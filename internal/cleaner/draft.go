@@ -0,0 +1,53 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+)
+
+// DraftRule marks notifications of draft pull requests as done, unless the
+// authenticated user is the author or was explicitly asked to review.
+type DraftRule struct{}
+
+// NewDraftRule creates a new DraftRule.
+func NewDraftRule() *DraftRule {
+	return &DraftRule{}
+}
+
+// Name returns the rule name.
+func (r *DraftRule) Name() string {
+	return "draft"
+}
+
+// Evaluate checks if the pull request related to the notification is a draft.
+func (r *DraftRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	if t.GetSubject().GetType() != TypePullRequest {
+		return Skip(), nil
+	}
+
+	pr, err := t.PullRequest(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+
+	if !pr.GetDraft() || t.GetReason() == ReasonReviewRequested {
+		return Skip(), nil
+	}
+
+	login, err := t.Viewer().Login(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+
+	if pr.GetUser().GetLogin() == login {
+		return Skip(), nil
+	}
+
+	for _, reviewer := range pr.RequestedReviewers {
+		if reviewer.GetLogin() == login {
+			return Skip(), nil
+		}
+	}
+
+	return Done(fmt.Sprintf("pull request #%d is a draft", pr.GetNumber())), nil
+}
@@ -0,0 +1,148 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestDraftRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		reason   string
+		pr       map[string]any
+		expected cleaner.Outcome
+	}{
+		{
+			name:     "marks teammate draft pull requests as done",
+			reason:   cleaner.ReasonSubscribed,
+			pr:       map[string]any{"draft": true, "user": map[string]any{"login": "teammate"}},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name:     "keeps own draft pull requests",
+			reason:   cleaner.ReasonAuthor,
+			pr:       map[string]any{"draft": true, "user": map[string]any{"login": "me"}},
+			expected: cleaner.OutcomeSkip,
+		},
+		{
+			name:   "keeps draft pull requests with review requested",
+			reason: cleaner.ReasonSubscribed,
+			pr: map[string]any{
+				"draft":               true,
+				"user":                map[string]any{"login": "teammate"},
+				"requested_reviewers": []map[string]any{{"login": "me"}},
+			},
+			expected: cleaner.OutcomeSkip,
+		},
+		{
+			name:     "skips ready pull requests",
+			reason:   cleaner.ReasonSubscribed,
+			pr:       map[string]any{"draft": false, "user": map[string]any{"login": "teammate"}},
+			expected: cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/repos/owner/repo/pulls/1").
+				Reply(200).
+				JSON(tc.pr)
+
+			gock.New("https://api.github.com").
+				Get("/user").
+				Reply(200).
+				JSON(map[string]any{"login": "me"})
+
+			n := &github.Notification{
+				ID:     github.Ptr("1"),
+				Reason: github.Ptr(tc.reason),
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypePullRequest),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/pulls/1"),
+				},
+			}
+
+			decision, err := cleaner.NewDraftRule().Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+		})
+	}
+
+	t.Run("keeps draft pull requests for review requested notifications", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/pulls/1").
+			Reply(200).
+			JSON(map[string]any{"draft": true})
+
+		n := &github.Notification{
+			ID:     github.Ptr("1"),
+			Reason: github.Ptr(cleaner.ReasonReviewRequested),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypePullRequest),
+				URL:  github.Ptr("https://api.github.com/repos/owner/repo/pulls/1"),
+			},
+		}
+
+		decision, err := cleaner.NewDraftRule().Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+}
+
+func TestClean_FetchesViewerOnce(t *testing.T) {
+	defer gock.Off()
+
+	notifications := make([]*github.Notification, 0, 2)
+	for _, id := range []string{"1", "2"} {
+		notifications = append(notifications, &github.Notification{
+			ID:     github.Ptr(id),
+			Reason: github.Ptr(cleaner.ReasonSubscribed),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypePullRequest),
+				URL:  github.Ptr("https://api.github.com/repos/owner/repo/pulls/" + id),
+			},
+		})
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/pulls/" + id).
+			Reply(200).
+			JSON(map[string]any{"draft": true, "user": map[string]any{"login": "teammate"}})
+
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/" + id).
+			Reply(204)
+	}
+
+	gock.New("https://api.github.com").
+		Get("/notifications").
+		Reply(200).
+		JSON(notifications)
+
+	// A single mock: the authenticated user must be fetched only once.
+	gock.New("https://api.github.com").
+		Get("/user").
+		Reply(200).
+		JSON(map[string]any{"login": "me"})
+
+	nc := cleaner.NewNotificationsCleaner(
+		cleaner.WithGitHubClient(setupMockClient(t)),
+		cleaner.WithRules(cleaner.NewDraftRule()),
+	)
+
+	err := nc.Clean(context.Background())
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,55 @@
+package cleaner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// durationPartPattern matches a single number and unit of a duration, such as "36h" or "1.5d".
+var durationPartPattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)([a-zµ]+)`)
+
+// ParseDuration parses a human duration such as "36h", "2w" or "1d12h".
+// In addition to the units of time.ParseDuration, it accepts "d" (24 hours) and "w" (7 days).
+// Negative durations are not supported.
+func ParseDuration(s string) (time.Duration, error) {
+	if s == "0" {
+		return 0, nil
+	}
+	if s == "" {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	var total time.Duration
+	for rest := s; rest != ""; {
+		m := durationPartPattern.FindStringSubmatch(rest)
+		if m == nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		rest = rest[len(m[0]):]
+
+		var unit time.Duration
+		switch m[2] {
+		case "d":
+			unit = 24 * time.Hour
+		case "w":
+			unit = 7 * 24 * time.Hour
+		default:
+			d, err := time.ParseDuration(m[0])
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q: unknown unit %q", s, m[2])
+			}
+			total += d
+			continue
+		}
+
+		n, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		total += time.Duration(n * float64(unit))
+	}
+
+	return total, nil
+}
@@ -0,0 +1,41 @@
+package cleaner_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"36h", 36 * time.Hour},
+		{"90m", 90 * time.Minute},
+		{"2w", 14 * 24 * time.Hour},
+		{"1d12h", 36 * time.Hour},
+		{"1.5d", 36 * time.Hour},
+		{"1w2d3h4m", (9*24+3)*time.Hour + 4*time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			d, err := cleaner.ParseDuration(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, d)
+		})
+	}
+
+	for _, input := range []string{"", "12", "-1d", "1y", "d", "1d 2h"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := cleaner.ParseDuration(input)
+			assert.Error(t, err)
+		})
+	}
+}
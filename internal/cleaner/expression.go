@@ -0,0 +1,203 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/ast"
+	"github.com/expr-lang/expr/vm"
+	"github.com/google/go-github/v69/github"
+)
+
+// ExpressionRule marks notifications as done when a boolean expression evaluates to true.
+// Expressions use the expr language (https://expr-lang.org) and are evaluated against
+// the fields of ExpressionEnv. For example:
+//
+//	repo.owner == "kubernetes" && reason == "subscribed" && age > duration("72h")
+type ExpressionRule struct {
+	Expression string
+
+	program *vm.Program
+	// needsSubject is true when the expression references the subject, which requires an API call.
+	needsSubject bool
+}
+
+// ExpressionEnv defines the variables available to expressions.
+type ExpressionEnv struct {
+	ID        string        `expr:"id"`
+	Reason    string        `expr:"reason"`
+	Unread    bool          `expr:"unread"`
+	Title     string        `expr:"title"`
+	Type      string        `expr:"type"`
+	UpdatedAt time.Time     `expr:"updated_at"`
+	Age       time.Duration `expr:"age"`
+	// LastReadAt and ReadAge are zero when the notification was never read.
+	LastReadAt time.Time         `expr:"last_read_at"`
+	ReadAge    time.Duration     `expr:"read_age"`
+	Repo       ExpressionRepo    `expr:"repo"`
+	Subject    ExpressionSubject `expr:"subject"`
+}
+
+// ExpressionRepo defines the repository fields available to expressions.
+type ExpressionRepo struct {
+	Owner    string `expr:"owner"`
+	Name     string `expr:"name"`
+	FullName string `expr:"full_name"`
+	Private  bool   `expr:"private"`
+	Fork     bool   `expr:"fork"`
+}
+
+// ExpressionSubject defines the fields of the related issue or pull request available to expressions.
+// It is only populated for notifications of issues and pull requests.
+type ExpressionSubject struct {
+	Number int    `expr:"number"`
+	State  string `expr:"state"`
+	// ClosedState is one of the ClosedState values, or empty when the subject is open.
+	ClosedState string    `expr:"closed_state"`
+	Author      string    `expr:"author"`
+	Labels      []string  `expr:"labels"`
+	Locked      bool      `expr:"locked"`
+	Draft       bool      `expr:"draft"`
+	Merged      bool      `expr:"merged"`
+	CreatedAt   time.Time `expr:"created_at"`
+	UpdatedAt   time.Time `expr:"updated_at"`
+	ClosedAt    time.Time `expr:"closed_at"`
+}
+
+// NewExpressionRule compiles the given expression into a new ExpressionRule.
+func NewExpressionRule(expression string) (*ExpressionRule, error) {
+	program, err := expr.Compile(expression, expr.Env(ExpressionEnv{}), expr.AsBool())
+	if err != nil {
+		return nil, fmt.Errorf("error compiling expression %q: %w", expression, err)
+	}
+
+	v := &identifierVisitor{name: "subject"}
+	node := program.Node()
+	ast.Walk(&node, v)
+
+	return &ExpressionRule{
+		Expression:   expression,
+		program:      program,
+		needsSubject: v.found,
+	}, nil
+}
+
+// Name returns the rule name.
+func (r *ExpressionRule) Name() string {
+	return "expression"
+}
+
+// Evaluate runs the expression against the notification.
+func (r *ExpressionRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	env, err := r.env(ctx, t)
+	if err != nil {
+		return Skip(), err
+	}
+
+	out, err := expr.Run(r.program, env)
+	if err != nil {
+		return Skip(), fmt.Errorf("error evaluating expression %q: %w", r.Expression, err)
+	}
+
+	if match, ok := out.(bool); ok && match {
+		return Done(fmt.Sprintf("expression %q matched", r.Expression)), nil
+	}
+
+	return Skip(), nil
+}
+
+// env builds the expression environment for the given thread.
+func (r *ExpressionRule) env(ctx context.Context, t *Thread) (ExpressionEnv, error) {
+	repo := t.GetRepository()
+	env := ExpressionEnv{
+		ID:        t.GetID(),
+		Reason:    t.GetReason(),
+		Unread:    t.GetUnread(),
+		Title:     t.GetSubject().GetTitle(),
+		Type:      t.GetSubject().GetType(),
+		UpdatedAt: t.GetUpdatedAt().Time,
+		Repo: ExpressionRepo{
+			Owner:    repo.GetOwner().GetLogin(),
+			Name:     repo.GetName(),
+			FullName: repo.GetFullName(),
+			Private:  repo.GetPrivate(),
+			Fork:     repo.GetFork(),
+		},
+	}
+
+	if t.UpdatedAt != nil {
+		env.Age = time.Since(t.UpdatedAt.Time)
+	}
+
+	if t.LastReadAt != nil {
+		env.LastReadAt = t.LastReadAt.Time
+		env.ReadAge = time.Since(t.LastReadAt.Time)
+	}
+
+	if !r.needsSubject {
+		return env, nil
+	}
+
+	switch env.Type {
+	case TypePullRequest:
+		pr, err := t.PullRequest(ctx)
+		if err != nil {
+			return env, err
+		}
+		env.Subject = ExpressionSubject{
+			Number:      pr.GetNumber(),
+			State:       pr.GetState(),
+			ClosedState: string(pullRequestClosedState(pr)),
+			Author:      pr.GetUser().GetLogin(),
+			Labels:      labelNames(pr.Labels),
+			Locked:      pr.GetLocked(),
+			Draft:       pr.GetDraft(),
+			Merged:      pr.GetMerged(),
+			CreatedAt:   pr.GetCreatedAt().Time,
+			UpdatedAt:   pr.GetUpdatedAt().Time,
+			ClosedAt:    pr.GetClosedAt().Time,
+		}
+	case TypeIssue:
+		issue, err := t.Issue(ctx)
+		if err != nil {
+			return env, err
+		}
+		env.Subject = ExpressionSubject{
+			Number:      issue.GetNumber(),
+			State:       issue.GetState(),
+			ClosedState: string(issueClosedState(issue)),
+			Author:      issue.GetUser().GetLogin(),
+			Labels:      labelNames(issue.Labels),
+			Locked:      issue.GetLocked(),
+			CreatedAt:   issue.GetCreatedAt().Time,
+			UpdatedAt:   issue.GetUpdatedAt().Time,
+			ClosedAt:    issue.GetClosedAt().Time,
+		}
+	}
+
+	return env, nil
+}
+
+// identifierVisitor looks for an identifier with the given name in an expression.
+type identifierVisitor struct {
+	name  string
+	found bool
+}
+
+// Visit implements ast.Visitor.
+func (v *identifierVisitor) Visit(node *ast.Node) {
+	if ident, ok := (*node).(*ast.IdentifierNode); ok && ident.Value == v.name {
+		v.found = true
+	}
+}
+
+// labelNames returns the names of the given labels.
+func labelNames(labels []*github.Label) []string {
+	names := make([]string, 0, len(labels))
+	for _, l := range labels {
+		names = append(names, l.GetName())
+	}
+	return names
+}
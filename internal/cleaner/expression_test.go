@@ -0,0 +1,113 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestNewExpressionRule(t *testing.T) {
+	t.Run("compiles valid expression", func(t *testing.T) {
+		rule, err := cleaner.NewExpressionRule(`reason == "subscribed" && age > duration("72h")`)
+		require.NoError(t, err)
+		assert.Equal(t, "expression", rule.Name())
+	})
+
+	t.Run("returns error for unknown fields", func(t *testing.T) {
+		_, err := cleaner.NewExpressionRule(`unknown == "value"`)
+		assert.Error(t, err)
+	})
+
+	t.Run("returns error for non boolean expressions", func(t *testing.T) {
+		_, err := cleaner.NewExpressionRule(`reason`)
+		assert.Error(t, err)
+	})
+}
+
+func TestExpressionRule_Evaluate(t *testing.T) {
+	notification := &github.Notification{
+		ID:        github.Ptr("1"),
+		Reason:    github.Ptr("subscribed"),
+		UpdatedAt: &github.Timestamp{Time: time.Now().Add(-96 * time.Hour)},
+		Repository: &github.Repository{
+			Name:     github.Ptr("kubernetes"),
+			FullName: github.Ptr("kubernetes/kubernetes"),
+			Owner:    &github.User{Login: github.Ptr("kubernetes")},
+		},
+		Subject: &github.NotificationSubject{
+			Type: github.Ptr(cleaner.TypePullRequest),
+			URL:  github.Ptr("https://api.github.com/repos/kubernetes/kubernetes/pulls/1"),
+		},
+	}
+
+	t.Run("matches notification fields", func(t *testing.T) {
+		rule, err := cleaner.NewExpressionRule(`repo.owner == "kubernetes" && reason == "subscribed" && age > duration("72h")`)
+		require.NoError(t, err)
+
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), notification))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+	})
+
+	t.Run("does not match", func(t *testing.T) {
+		rule, err := cleaner.NewExpressionRule(`reason == "mention"`)
+		require.NoError(t, err)
+
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), notification))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+	})
+
+	t.Run("does not match age without update time", func(t *testing.T) {
+		n := *notification
+		n.UpdatedAt = nil
+
+		rule, err := cleaner.NewExpressionRule(`age > duration("72h")`)
+		require.NoError(t, err)
+
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), &n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+	})
+
+	t.Run("matches read state", func(t *testing.T) {
+		n := *notification
+		n.Unread = github.Ptr(false)
+		n.LastReadAt = &github.Timestamp{Time: time.Now().Add(-48 * time.Hour)}
+
+		rule, err := cleaner.NewExpressionRule(`!unread && read_age > duration("24h") && last_read_at > updated_at`)
+		require.NoError(t, err)
+
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), &n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+	})
+
+	t.Run("fetches the subject when referenced", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/kubernetes/kubernetes/pulls/1").
+			Reply(200).
+			JSON(map[string]any{
+				"state":  "closed",
+				"merged": true,
+				"labels": []map[string]any{{"name": "lgtm"}},
+			})
+
+		rule, err := cleaner.NewExpressionRule(`subject.merged && "lgtm" in subject.labels`)
+		require.NoError(t, err)
+
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), notification))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+}
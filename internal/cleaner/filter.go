@@ -0,0 +1,55 @@
+package cleaner
+
+import (
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Filter restricts which notifications are processed by the cleaner.
+// Notifications are filtered before any rule is evaluated, so excluded
+// notifications cost no API calls.
+//
+// Repository patterns are matched against the repository full name (owner/repo)
+// and organization patterns against the repository owner, using the syntax of
+// path.Match. For example, "owner/*" matches every repository of owner.
+type Filter struct {
+	// Repos lists the repositories to process. When both Repos and Orgs are empty,
+	// all repositories are processed. Otherwise, a notification is processed when it
+	// matches either Repos or Orgs.
+	Repos []string
+	// ExcludeRepos lists the repositories to ignore. It takes precedence over Repos and Orgs.
+	ExcludeRepos []string
+	// Orgs lists the organizations (or users) to process.
+	Orgs []string
+	// ExcludeOrgs lists the organizations (or users) to ignore. It takes precedence over Repos and Orgs.
+	ExcludeOrgs []string
+}
+
+// Match reports whether the notification should be processed.
+func (f Filter) Match(n *github.Notification) bool {
+	repo := n.GetRepository().GetFullName()
+	owner, _, _ := strings.Cut(repo, "/")
+
+	if matchAny(f.ExcludeRepos, repo) || matchAny(f.ExcludeOrgs, owner) {
+		return false
+	}
+
+	if len(f.Repos) == 0 && len(f.Orgs) == 0 {
+		return true
+	}
+
+	return matchAny(f.Repos, repo) || matchAny(f.Orgs, owner)
+}
+
+// matchAny reports whether name matches any of the given glob patterns.
+// Invalid patterns never match.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, err := path.Match(pattern, name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
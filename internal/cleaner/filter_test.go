@@ -0,0 +1,52 @@
+package cleaner_test
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestFilter_Match(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filter   cleaner.Filter
+		repo     string
+		expected bool
+	}{
+		{"empty filter matches everything", cleaner.Filter{}, "owner/repo", true},
+		{"matches included repository", cleaner.Filter{Repos: []string{"owner/*"}}, "owner/repo", true},
+		{"does not match other repositories", cleaner.Filter{Repos: []string{"owner/*"}}, "other/repo", false},
+		{"matches included organization", cleaner.Filter{Orgs: []string{"my-*"}}, "my-org/repo", true},
+		{"does not match other organizations", cleaner.Filter{Orgs: []string{"my-org"}}, "other/repo", false},
+		{
+			"matches either included repositories or organizations",
+			cleaner.Filter{Repos: []string{"kubernetes/kubernetes"}, Orgs: []string{"my-org"}},
+			"kubernetes/kubernetes",
+			true,
+		},
+		{
+			"excluded repository takes precedence",
+			cleaner.Filter{Orgs: []string{"my-org"}, ExcludeRepos: []string{"my-org/sandbox-*"}},
+			"my-org/sandbox-1",
+			false,
+		},
+		{
+			"excluded organization takes precedence",
+			cleaner.Filter{Repos: []string{"*/repo"}, ExcludeOrgs: []string{"other"}},
+			"other/repo",
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &github.Notification{
+				Repository: &github.Repository{FullName: github.Ptr(tc.repo)},
+			}
+			assert.Equal(t, tc.expected, tc.filter.Match(n))
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// graphQLRequest is the body of a GitHub GraphQL API request.
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// graphQLResponse is the body of a GitHub GraphQL API response.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// queryGraphQL runs a query against the GitHub GraphQL API, using the
+// authentication of the given REST client, and decodes the response data into out.
+func queryGraphQL(ctx context.Context, client *github.Client, query string, variables map[string]any, out any) error {
+	req, err := client.NewRequest("POST", "graphql", graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	if _, err := client.Do(ctx, req, &resp); err != nil {
+		return err
+	}
+
+	if len(resp.Errors) > 0 {
+		messages := make([]string, 0, len(resp.Errors))
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return errors.New(strings.Join(messages, "; "))
+	}
+
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("error decoding GraphQL response: %w", err)
+	}
+
+	return nil
+}
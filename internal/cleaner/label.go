@@ -0,0 +1,61 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// LabelRule applies an outcome to notifications of issues and pull requests with one of the given labels.
+// For example, it can keep anything labeled "security" or mark anything labeled "wontfix" as done.
+// Labels are matched case-insensitively, using the subject already fetched by the other rules.
+type LabelRule struct {
+	Labels  []string
+	Outcome Outcome
+}
+
+// NewLabelRule creates a new LabelRule.
+func NewLabelRule(labels []string, outcome Outcome) *LabelRule {
+	return &LabelRule{
+		Labels:  labels,
+		Outcome: outcome,
+	}
+}
+
+// Name returns the rule name.
+func (r *LabelRule) Name() string {
+	return "label"
+}
+
+// Evaluate checks the labels of the issue or pull request related to the notification.
+func (r *LabelRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	var labels []*github.Label
+	switch t.GetSubject().GetType() {
+	case TypePullRequest:
+		pr, err := t.PullRequest(ctx)
+		if err != nil {
+			return Skip(), err
+		}
+		labels = pr.Labels
+	case TypeIssue:
+		issue, err := t.Issue(ctx)
+		if err != nil {
+			return Skip(), err
+		}
+		labels = issue.Labels
+	default:
+		return Skip(), nil
+	}
+
+	for _, label := range labels {
+		name := label.GetName()
+		if slices.ContainsFunc(r.Labels, func(l string) bool { return strings.EqualFold(l, name) }) {
+			return Decision{Outcome: r.Outcome, Reason: fmt.Sprintf("labeled %s", name)}, nil
+		}
+	}
+
+	return Skip(), nil
+}
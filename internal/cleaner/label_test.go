@@ -0,0 +1,93 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestLabelRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     *cleaner.LabelRule
+		labels   []map[string]any
+		expected cleaner.Outcome
+	}{
+		{
+			name:     "keeps issues with protected labels",
+			rule:     cleaner.NewLabelRule([]string{"security", "P0"}, cleaner.OutcomeKeep),
+			labels:   []map[string]any{{"name": "bug"}, {"name": "p0"}},
+			expected: cleaner.OutcomeKeep,
+		},
+		{
+			name:     "marks issues with clean labels as done",
+			rule:     cleaner.NewLabelRule([]string{"wontfix", "stale"}, cleaner.OutcomeDone),
+			labels:   []map[string]any{{"name": "stale"}},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name:     "skips issues without matching labels",
+			rule:     cleaner.NewLabelRule([]string{"wontfix"}, cleaner.OutcomeDone),
+			labels:   []map[string]any{{"name": "bug"}},
+			expected: cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/repos/owner/repo/issues/1").
+				Reply(200).
+				JSON(map[string]any{"state": "open", "labels": tc.labels})
+
+			n := &github.Notification{
+				ID: github.Ptr("1"),
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypeIssue),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/issues/1"),
+				},
+			}
+
+			decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.True(t, gock.IsDone())
+		})
+	}
+
+	t.Run("reuses the subject fetched by previous rules", func(t *testing.T) {
+		defer gock.Off()
+
+		// A single mock: the issue must be fetched only once for both rules.
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/issues/1").
+			Reply(200).
+			JSON(map[string]any{"state": "closed", "labels": []map[string]any{{"name": "security"}}})
+
+		n := &github.Notification{
+			ID: github.Ptr("1"),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypeIssue),
+				URL:  github.Ptr("https://api.github.com/repos/owner/repo/issues/1"),
+			},
+		}
+		thread := cleaner.NewThread(setupMockClient(t), n)
+
+		decision, err := cleaner.NewClosedSubjectRule(cleaner.StateMerged).Evaluate(context.Background(), thread)
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+
+		decision, err = cleaner.NewLabelRule([]string{"security"}, cleaner.OutcomeKeep).Evaluate(context.Background(), thread)
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeKeep, decision.Outcome)
+		assert.True(t, gock.IsDone())
+	})
+}
@@ -0,0 +1,187 @@
+package cleaner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ActionIgnoreRepository is recorded when the subscription of a noisy repository is set to ignored.
+// It is not a rule action, see NoisyRepos.
+const ActionIgnoreRepository Action = "ignore_repository"
+
+// NoisyRepos configures the detection of repositories where the cleaner cleans many notifications.
+// Noisy repositories are reported with a recommendation to mute them or, when Mute is set,
+// muted by setting the repository subscription to ignored.
+type NoisyRepos struct {
+	// Threshold is the number of cleaned notifications from which a repository is noisy.
+	// When zero, noisy repositories are not detected.
+	Threshold int
+	// Mute sets the subscription of noisy repositories to ignored, instead of only recommending it.
+	Mute bool
+	// HistoryPath is the file where the counts of each run are kept. When set, repositories
+	// are counted over the runs of the last Window instead of the current run only.
+	HistoryPath string
+	// Window is the period of history over which repositories are counted. When zero, all the history is used.
+	Window time.Duration
+}
+
+// repoHistory is the content of the history file.
+type repoHistory struct {
+	Runs []historyRun `json:"runs"`
+}
+
+// historyRun holds the number of notifications cleaned per repository during a run.
+type historyRun struct {
+	Time   time.Time      `json:"time"`
+	Counts map[string]int `json:"counts"`
+}
+
+// handleNoisyRepos reports, or mutes, the repositories with at least Threshold cleaned notifications.
+// counts holds the number of notifications cleaned per repository during the current run.
+func (nc *NotificationsCleaner) handleNoisyRepos(ctx context.Context, counts map[string]int) error {
+	if nc.NoisyRepos.Threshold <= 0 {
+		return nil
+	}
+
+	path := nc.NoisyRepos.HistoryPath
+	if path == "" {
+		nc.muteNoisyRepos(ctx, counts)
+		return nil
+	}
+
+	history, err := readHistory(path)
+	if err != nil {
+		return err
+	}
+
+	totals := history.add(counts, time.Now(), nc.NoisyRepos.Window)
+	muted := nc.muteNoisyRepos(ctx, totals)
+	if nc.DryRun {
+		return nil
+	}
+
+	// Muted repositories are counted from scratch, so that the next runs do not mute them
+	// again, in particular after their subscription was restored.
+	history.forget(muted)
+	return writeHistory(path, history)
+}
+
+// muteNoisyRepos reports, or mutes, the repositories of totals with at least Threshold cleaned notifications.
+// It returns the repositories that were muted.
+func (nc *NotificationsCleaner) muteNoisyRepos(ctx context.Context, totals map[string]int) []string {
+	var muted []string
+	for _, repo := range slices.Sorted(maps.Keys(totals)) {
+		count := totals[repo]
+		if count < nc.NoisyRepos.Threshold {
+			continue
+		}
+
+		attrs := []any{
+			slog.String("repository", repo),
+			slog.Int("cleaned", count),
+		}
+		if !nc.NoisyRepos.Mute || nc.DryRun {
+			slog.Warn("noisy repository, consider muting it", attrs...)
+			continue
+		}
+
+		slog.Info("muting noisy repository", attrs...)
+		if err := nc.muteRepository(ctx, repo); err != nil {
+			slog.Error("error muting repository",
+				slog.String("repository", repo),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		muted = append(muted, repo)
+	}
+	return muted
+}
+
+// muteRepository sets the subscription of the repository to ignored.
+// The change is recorded beforehand, along with the previous subscription, when a Recorder is set.
+func (nc *NotificationsCleaner) muteRepository(ctx context.Context, repo string) error {
+	if nc.Recorder != nil {
+		previous, err := repositorySubscription(ctx, nc.GitHubClient, repo)
+		if err != nil {
+			return err
+		}
+		if err := nc.recordChange(SubscriptionChange{Repository: repo, Action: ActionIgnoreRepository, Previous: previous}); err != nil {
+			return err
+		}
+	}
+
+	owner, name := splitRepository(repo)
+	_, _, err := nc.GitHubClient.Activity.SetRepositorySubscription(ctx, owner, name, &github.Subscription{Ignored: github.Ptr(true)})
+	return err
+}
+
+// add adds the counts of a run to the history, removing the runs older than window when it is set,
+// and returns the counts per repository over the history.
+func (h *repoHistory) add(counts map[string]int, now time.Time, window time.Duration) map[string]int {
+	if window > 0 {
+		h.Runs = slices.DeleteFunc(h.Runs, func(r historyRun) bool {
+			return r.Time.Before(now.Add(-window))
+		})
+	}
+	h.Runs = append(h.Runs, historyRun{Time: now, Counts: counts})
+
+	totals := make(map[string]int)
+	for _, run := range h.Runs {
+		for repo, count := range run.Counts {
+			totals[repo] += count
+		}
+	}
+	return totals
+}
+
+// forget removes the counts of the given repositories from the history.
+func (h *repoHistory) forget(repos []string) {
+	for _, run := range h.Runs {
+		for _, repo := range repos {
+			delete(run.Counts, repo)
+		}
+	}
+}
+
+// readHistory reads the history file, returning an empty history when it does not exist.
+func readHistory(path string) (repoHistory, error) {
+	var history repoHistory
+	data, err := os.ReadFile(filepath.Clean(path))
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return history, fmt.Errorf("error reading history file: %w", err)
+	default:
+		if err := json.Unmarshal(data, &history); err != nil {
+			return history, fmt.Errorf("error parsing history file %s: %w", path, err)
+		}
+	}
+	return history, nil
+}
+
+// writeHistory writes the history file, creating its directory if needed.
+func writeHistory(path string, history repoHistory) error {
+	data, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("error encoding history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error creating directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing history file: %w", err)
+	}
+	return nil
+}
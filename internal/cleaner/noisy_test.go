@@ -0,0 +1,182 @@
+package cleaner_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+// mockNotifications mocks the notifications list with count notifications from the given repository.
+func mockNotifications(repo string, count int) {
+	notifications := make([]*github.Notification, 0, count)
+	for i := range count {
+		notifications = append(notifications, &github.Notification{
+			ID:         github.Ptr(strconv.Itoa(i + 1)),
+			Repository: &github.Repository{FullName: github.Ptr(repo)},
+		})
+	}
+
+	gock.New("https://api.github.com").
+		Get("/notifications").
+		Reply(200).
+		JSON(notifications)
+}
+
+func TestClean_NoisyRepos(t *testing.T) {
+	doneRule := &stubRule{name: "done", decision: cleaner.Done("stub")}
+
+	t.Run("recommends muting noisy repositories", func(t *testing.T) {
+		defer gock.Off()
+
+		mockNotifications("owner/noisy", 2)
+
+		nc := cleaner.NewNotificationsCleaner(
+			cleaner.WithGitHubClient(setupMockClient(t)),
+			cleaner.WithRules(doneRule),
+			cleaner.WithDryRun(true),
+			cleaner.WithNoisyRepos(cleaner.NoisyRepos{Threshold: 2, Mute: true}),
+		)
+
+		// No subscription change expected in dry-run mode.
+		err := nc.Clean(context.Background())
+		require.NoError(t, err)
+		assert.True(t, gock.IsDone())
+		assert.False(t, gock.HasUnmatchedRequest())
+	})
+
+	t.Run("mutes noisy repositories when enabled", func(t *testing.T) {
+		defer gock.Off()
+
+		mockNotifications("owner/noisy", 2)
+
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/1").
+			Reply(204)
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/2").
+			Reply(204)
+		gock.New("https://api.github.com").
+			Get("/repos/owner/noisy/subscription").
+			Reply(404)
+		gock.New("https://api.github.com").
+			Put("/repos/owner/noisy/subscription").
+			JSON(map[string]any{"ignored": true}).
+			Reply(200).
+			JSON(map[string]any{"ignored": true})
+
+		p := filepath.Join(t.TempDir(), "journal.jsonl")
+		nc := cleaner.NewNotificationsCleaner(
+			cleaner.WithGitHubClient(setupMockClient(t)),
+			cleaner.WithRules(doneRule),
+			cleaner.WithRecorder(cleaner.NewFileRecorder(p)),
+			cleaner.WithNoisyRepos(cleaner.NoisyRepos{Threshold: 2, Mute: true}),
+		)
+
+		err := nc.Clean(context.Background())
+		require.NoError(t, err)
+		assert.True(t, gock.IsDone())
+
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		var change cleaner.SubscriptionChange
+		require.NoError(t, json.Unmarshal(data, &change))
+		assert.Equal(t, "owner/noisy", change.Repository)
+		assert.Equal(t, cleaner.ActionIgnoreRepository, change.Action)
+		assert.Nil(t, change.Previous, "expected no previous subscription")
+	})
+
+	t.Run("counts cleaned notifications over the history window", func(t *testing.T) {
+		defer gock.Off()
+
+		history := filepath.Join(t.TempDir(), "history.json")
+		data, err := json.Marshal(map[string]any{
+			"runs": []map[string]any{
+				{"time": time.Now().AddDate(0, 0, -30), "counts": map[string]int{"owner/noisy": 100}},
+				{"time": time.Now().AddDate(0, 0, -1), "counts": map[string]int{"owner/noisy": 1}},
+			},
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(history, data, 0o600))
+
+		mockNotifications("owner/noisy", 1)
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/1").
+			Reply(204)
+
+		nc := cleaner.NewNotificationsCleaner(
+			cleaner.WithGitHubClient(setupMockClient(t)),
+			cleaner.WithRules(doneRule),
+			cleaner.WithNoisyRepos(cleaner.NoisyRepos{
+				Threshold:   2,
+				HistoryPath: history,
+				Window:      7 * 24 * time.Hour,
+			}),
+		)
+
+		err = nc.Clean(context.Background())
+		require.NoError(t, err)
+		assert.True(t, gock.IsDone())
+
+		var saved struct {
+			Runs []struct {
+				Counts map[string]int `json:"counts"`
+			} `json:"runs"`
+		}
+		data, err = os.ReadFile(history)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &saved))
+		require.Len(t, saved.Runs, 2, "expected runs outside of the window to be removed")
+		assert.Equal(t, map[string]int{"owner/noisy": 1}, saved.Runs[1].Counts)
+	})
+
+	t.Run("does not mute repositories again in the next runs", func(t *testing.T) {
+		defer gock.Off()
+
+		history := filepath.Join(t.TempDir(), "history.json")
+		nc := cleaner.NewNotificationsCleaner(
+			cleaner.WithGitHubClient(setupMockClient(t)),
+			cleaner.WithRules(doneRule),
+			cleaner.WithNoisyRepos(cleaner.NoisyRepos{
+				Threshold:   2,
+				Mute:        true,
+				HistoryPath: history,
+			}),
+		)
+
+		mockNotifications("owner/noisy", 2)
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/[12]").
+			Times(2).
+			Reply(204)
+		gock.New("https://api.github.com").
+			Put("/repos/owner/noisy/subscription").
+			Reply(200).
+			JSON(map[string]any{"ignored": true})
+
+		err := nc.Clean(context.Background())
+		require.NoError(t, err)
+		assert.True(t, gock.IsDone())
+
+		// The repository is below the threshold again, for example after its subscription was restored.
+		mockNotifications("owner/noisy", 1)
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/1").
+			Reply(204)
+
+		err = nc.Clean(context.Background())
+		require.NoError(t, err)
+		assert.True(t, gock.IsDone())
+		assert.False(t, gock.HasUnmatchedRequest(), "expected the repository not to be muted again")
+	})
+}
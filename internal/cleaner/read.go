@@ -0,0 +1,43 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ReadRule marks notifications as done based on their read state, so that
+// notifications already seen can be retired sooner than unread ones.
+type ReadRule struct {
+	// Unread selects the notifications matched by the rule:
+	//   - false: notifications read more than MaxAge ago and not updated since.
+	//   - true: unread notifications not updated for longer than MaxAge.
+	Unread bool
+	MaxAge time.Duration
+}
+
+// Name returns the rule name.
+func (r *ReadRule) Name() string {
+	return "read"
+}
+
+// Evaluate checks the read state of the notification.
+func (r *ReadRule) Evaluate(_ context.Context, t *Thread) (Decision, error) {
+	if r.Unread {
+		if !t.GetUnread() || !olderThan(t, r.MaxAge) {
+			return Skip(), nil
+		}
+		return Done(fmt.Sprintf("unread and not updated for more than %s", r.MaxAge)), nil
+	}
+
+	// GitHub marks a notification as unread again when it is updated, but
+	// LastReadAt is also checked in case the update did not flag it.
+	if t.GetUnread() || t.LastReadAt == nil || !t.LastReadAt.Before(time.Now().Add(-r.MaxAge)) {
+		return Skip(), nil
+	}
+	if t.UpdatedAt != nil && t.UpdatedAt.After(t.LastReadAt.Time) {
+		return Skip(), nil
+	}
+
+	return Done(fmt.Sprintf("read more than %s ago and not updated since", r.MaxAge)), nil
+}
@@ -0,0 +1,85 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestReadRule(t *testing.T) {
+	now := time.Now()
+	ts := func(d time.Duration) *github.Timestamp {
+		return &github.Timestamp{Time: now.Add(-d)}
+	}
+
+	testCases := []struct {
+		name       string
+		rule       *cleaner.ReadRule
+		unread     bool
+		updatedAt  *github.Timestamp
+		lastReadAt *github.Timestamp
+		expected   cleaner.Outcome
+	}{
+		{
+			name:       "marks notifications read long ago as done",
+			rule:       &cleaner.ReadRule{MaxAge: 7 * 24 * time.Hour},
+			updatedAt:  ts(10 * 24 * time.Hour),
+			lastReadAt: ts(8 * 24 * time.Hour),
+			expected:   cleaner.OutcomeDone,
+		},
+		{
+			name:       "skips notifications read recently",
+			rule:       &cleaner.ReadRule{MaxAge: 7 * 24 * time.Hour},
+			updatedAt:  ts(10 * 24 * time.Hour),
+			lastReadAt: ts(2 * 24 * time.Hour),
+			expected:   cleaner.OutcomeSkip,
+		},
+		{
+			name:       "skips notifications updated since they were read",
+			rule:       &cleaner.ReadRule{MaxAge: 7 * 24 * time.Hour},
+			updatedAt:  ts(8 * 24 * time.Hour),
+			lastReadAt: ts(9 * 24 * time.Hour),
+			expected:   cleaner.OutcomeSkip,
+		},
+		{
+			name:      "skips notifications never read",
+			rule:      &cleaner.ReadRule{MaxAge: 7 * 24 * time.Hour},
+			updatedAt: ts(10 * 24 * time.Hour),
+			expected:  cleaner.OutcomeSkip,
+		},
+		{
+			name:      "marks old unread notifications as done",
+			rule:      &cleaner.ReadRule{Unread: true, MaxAge: 30 * 24 * time.Hour},
+			unread:    true,
+			updatedAt: ts(31 * 24 * time.Hour),
+			expected:  cleaner.OutcomeDone,
+		},
+		{
+			name:       "unread rule skips read notifications",
+			rule:       &cleaner.ReadRule{Unread: true, MaxAge: 30 * 24 * time.Hour},
+			updatedAt:  ts(31 * 24 * time.Hour),
+			lastReadAt: ts(31 * 24 * time.Hour),
+			expected:   cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &github.Notification{
+				ID:         github.Ptr("1"),
+				Unread:     github.Ptr(tc.unread),
+				UpdatedAt:  tc.updatedAt,
+				LastReadAt: tc.lastReadAt,
+			}
+			decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(nil, n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+		})
+	}
+}
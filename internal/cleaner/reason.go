@@ -0,0 +1,89 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Notification reasons, as documented in
+// https://docs.github.com/en/rest/activity/notifications#about-notification-reasons
+const (
+	ReasonApprovalRequested      = "approval_requested"
+	ReasonAssign                 = "assign"
+	ReasonAuthor                 = "author"
+	ReasonCIActivity             = "ci_activity"
+	ReasonComment                = "comment"
+	ReasonInvitation             = "invitation"
+	ReasonManual                 = "manual"
+	ReasonMemberFeatureRequested = "member_feature_requested"
+	ReasonMention                = "mention"
+	ReasonReviewRequested        = "review_requested"
+	ReasonSecurityAdvisoryCredit = "security_advisory_credit"
+	ReasonSecurityAlert          = "security_alert"
+	ReasonStateChange            = "state_change"
+	ReasonSubscribed             = "subscribed"
+	ReasonTeamMention            = "team_mention"
+)
+
+// Reasons lists all the known notification reasons.
+var Reasons = []string{
+	ReasonApprovalRequested,
+	ReasonAssign,
+	ReasonAuthor,
+	ReasonCIActivity,
+	ReasonComment,
+	ReasonInvitation,
+	ReasonManual,
+	ReasonMemberFeatureRequested,
+	ReasonMention,
+	ReasonReviewRequested,
+	ReasonSecurityAdvisoryCredit,
+	ReasonSecurityAlert,
+	ReasonStateChange,
+	ReasonSubscribed,
+	ReasonTeamMention,
+}
+
+// ReasonRule applies an outcome to notifications with one of the given reasons.
+// For example, it can mark "ci_activity" notifications older than one day as done,
+// or keep every "mention" notification regardless of the following rules.
+type ReasonRule struct {
+	Reasons []string
+	// MaxAge restricts the rule to notifications not updated for longer than MaxAge.
+	// When zero, the rule applies regardless of the notification age.
+	MaxAge  time.Duration
+	Outcome Outcome
+}
+
+// NewReasonRule creates a new ReasonRule.
+func NewReasonRule(reasons []string, maxAge time.Duration, outcome Outcome) *ReasonRule {
+	return &ReasonRule{
+		Reasons: reasons,
+		MaxAge:  maxAge,
+		Outcome: outcome,
+	}
+}
+
+// Name returns the rule name.
+func (r *ReasonRule) Name() string {
+	return "reason"
+}
+
+// Evaluate checks the notification reason and age.
+func (r *ReasonRule) Evaluate(_ context.Context, t *Thread) (Decision, error) {
+	reason := t.GetReason()
+	if !slices.Contains(r.Reasons, reason) {
+		return Skip(), nil
+	}
+
+	if r.MaxAge > 0 {
+		if !olderThan(t, r.MaxAge) {
+			return Skip(), nil
+		}
+		return Decision{Outcome: r.Outcome, Reason: fmt.Sprintf("reason is %s and not updated for more than %s", reason, r.MaxAge)}, nil
+	}
+
+	return Decision{Outcome: r.Outcome, Reason: fmt.Sprintf("reason is %s", reason)}, nil
+}
@@ -0,0 +1,87 @@
+package cleaner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SubscriptionChange describes a change of a thread or repository subscription made by the cleaner.
+type SubscriptionChange struct {
+	Time time.Time `json:"time"`
+	// RunID identifies the run of the cleaner that made the change.
+	RunID string `json:"run_id"`
+	// ThreadID is empty for repository subscription changes.
+	ThreadID   string `json:"thread_id,omitempty"`
+	Repository string `json:"repository"`
+	Subject    string `json:"subject,omitempty"`
+	Action     Action `json:"action"`
+	// Previous is the subscription before the change, or nil when there was none.
+	Previous *SubscriptionState `json:"previous"`
+}
+
+// SubscriptionState is the state of a thread or repository subscription.
+type SubscriptionState struct {
+	Subscribed bool `json:"subscribed"`
+	Ignored    bool `json:"ignored"`
+}
+
+// Recorder records the subscription changes made by the cleaner, so that they can be reverted with Restore.
+type Recorder interface {
+	Record(change SubscriptionChange) error
+}
+
+// FileRecorder records subscription changes as JSON lines appended to a file, the journal.
+type FileRecorder struct {
+	Path string
+}
+
+// NewFileRecorder creates a new FileRecorder writing to the given path.
+func NewFileRecorder(path string) *FileRecorder {
+	return &FileRecorder{Path: path}
+}
+
+// Record appends the change to the file, creating it and its directory if needed.
+func (r *FileRecorder) Record(change SubscriptionChange) error {
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o700); err != nil {
+		return fmt.Errorf("error creating directory for %s: %w", r.Path, err)
+	}
+
+	f, err := os.OpenFile(r.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", r.Path, err)
+	}
+	defer f.Close()
+
+	if err := json.NewEncoder(f).Encode(change); err != nil {
+		return fmt.Errorf("error writing to %s: %w", r.Path, err)
+	}
+	return nil
+}
+
+// ReadJournal reads the subscription changes recorded by a FileRecorder, in recording order.
+func ReadJournal(path string) ([]SubscriptionChange, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("error opening journal: %w", err)
+	}
+	defer f.Close()
+
+	var changes []SubscriptionChange
+	dec := json.NewDecoder(f)
+	for {
+		var change SubscriptionChange
+		err := dec.Decode(&change)
+		if errors.Is(err, io.EOF) {
+			return changes, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing journal %s: %w", path, err)
+		}
+		changes = append(changes, change)
+	}
+}
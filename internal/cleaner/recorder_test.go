@@ -0,0 +1,208 @@
+package cleaner_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestFileRecorder(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "state", "journal.jsonl")
+	recorder := cleaner.NewFileRecorder(p)
+
+	require.NoError(t, recorder.Record(cleaner.SubscriptionChange{ThreadID: "1", Action: cleaner.ActionIgnore}))
+	require.NoError(t, recorder.Record(cleaner.SubscriptionChange{ThreadID: "2", Action: cleaner.ActionUnsubscribe}))
+
+	data, err := os.ReadFile(p)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+
+	var change cleaner.SubscriptionChange
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &change))
+	assert.Equal(t, "2", change.ThreadID)
+	assert.Equal(t, cleaner.ActionUnsubscribe, change.Action)
+}
+
+func TestClean_IgnoreClosed(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Get("/notifications").
+		Reply(200).
+		JSON([]*github.Notification{
+			{
+				ID:         github.Ptr("1"),
+				UpdatedAt:  &github.Timestamp{Time: time.Now()},
+				Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+				Subject: &github.NotificationSubject{
+					Title: github.Ptr("Closed issue"),
+					Type:  github.Ptr(cleaner.TypeIssue),
+					URL:   github.Ptr("https://api.github.com/repos/owner/repo/issues/1"),
+				},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Get("/repos/owner/repo/issues/1").
+		Reply(200).
+		JSON(map[string]any{"state": "closed"})
+
+	gock.New("https://api.github.com").
+		Get("/notifications/threads/1/subscription").
+		Reply(200).
+		JSON(map[string]any{"subscribed": true, "ignored": false})
+
+	gock.New("https://api.github.com").
+		Put("/notifications/threads/1/subscription").
+		JSON(map[string]any{"ignored": true}).
+		Reply(200).
+		JSON(map[string]any{"ignored": true})
+
+	gock.New("https://api.github.com").
+		Delete("/notifications/threads/1").
+		Reply(204)
+
+	p := filepath.Join(t.TempDir(), "journal.jsonl")
+	rule := cleaner.NewClosedSubjectRule()
+	rule.Ignore = true
+	nc := cleaner.NewNotificationsCleaner(
+		cleaner.WithGitHubClient(setupMockClient(t)),
+		cleaner.WithRules(rule),
+		cleaner.WithRecorder(cleaner.NewFileRecorder(p)),
+	)
+	nc.RunID = "run-1"
+
+	err := nc.Clean(context.Background())
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	data, err := os.ReadFile(p)
+	require.NoError(t, err)
+	var change cleaner.SubscriptionChange
+	require.NoError(t, json.Unmarshal(data, &change))
+	assert.Equal(t, "1", change.ThreadID)
+	assert.Equal(t, "owner/repo", change.Repository)
+	assert.Equal(t, "Closed issue", change.Subject)
+	assert.Equal(t, cleaner.ActionIgnore, change.Action)
+	assert.Equal(t, "run-1", change.RunID)
+	assert.Equal(t, &cleaner.SubscriptionState{Subscribed: true}, change.Previous)
+}
+
+func TestClean_RunID(t *testing.T) {
+	defer gock.Off()
+
+	for range 2 {
+		gock.New("https://api.github.com").
+			Get("/notifications").
+			Reply(200).
+			JSON([]*github.Notification{
+				{
+					ID:         github.Ptr("1"),
+					UpdatedAt:  &github.Timestamp{Time: time.Now()},
+					Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+				},
+			})
+		gock.New("https://api.github.com").
+			Get("/notifications/threads/1/subscription").
+			Reply(404)
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/1/subscription").
+			Reply(204)
+	}
+
+	p := filepath.Join(t.TempDir(), "journal.jsonl")
+	rule := cleaner.WithActions(&stubRule{name: "stub", decision: cleaner.Done("stub")}, cleaner.ActionUnsubscribe)
+	nc := cleaner.NewNotificationsCleaner(
+		cleaner.WithGitHubClient(setupMockClient(t)),
+		cleaner.WithRules(rule),
+		cleaner.WithRecorder(cleaner.NewFileRecorder(p)),
+	)
+
+	// Both runs start in the same second.
+	require.NoError(t, nc.Clean(context.Background()))
+	require.NoError(t, nc.Clean(context.Background()))
+	assert.True(t, gock.IsDone())
+	assert.Empty(t, nc.RunID, "expected the generated run ID not to be kept for the next runs")
+
+	changes, err := cleaner.ReadJournal(p)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.NotEqual(t, changes[0].RunID, changes[1].RunID)
+}
+
+func TestClean_JournalNotWritable(t *testing.T) {
+	defer gock.Off()
+
+	gock.New("https://api.github.com").
+		Get("/notifications").
+		Reply(200).
+		JSON([]*github.Notification{
+			{
+				ID:         github.Ptr("1"),
+				UpdatedAt:  &github.Timestamp{Time: time.Now()},
+				Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+				Subject:    &github.NotificationSubject{Title: github.Ptr("Noisy issue")},
+			},
+		})
+
+	gock.New("https://api.github.com").
+		Get("/notifications/threads/1/subscription").
+		Reply(200).
+		JSON(map[string]any{"subscribed": true})
+
+	// The thread is still marked as done, but not unsubscribed.
+	gock.New("https://api.github.com").
+		Delete("/notifications/threads/1").
+		Reply(204)
+
+	// The journal directory cannot be created under a regular file.
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	rule := cleaner.WithActions(&stubRule{name: "stub", decision: cleaner.Done("stub")}, cleaner.ActionUnsubscribe, cleaner.ActionDone)
+	nc := cleaner.NewNotificationsCleaner(
+		cleaner.WithGitHubClient(setupMockClient(t)),
+		cleaner.WithRules(rule),
+		cleaner.WithRecorder(cleaner.NewFileRecorder(filepath.Join(file, "journal.jsonl"))),
+	)
+
+	err := nc.Clean(context.Background())
+	require.NoError(t, err)
+	assert.True(t, gock.IsDone())
+	assert.False(t, gock.HasUnmatchedRequest(), "expected the subscription to be left unchanged")
+}
+
+func TestReadJournal(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "journal.jsonl")
+	recorder := cleaner.NewFileRecorder(p)
+
+	require.NoError(t, recorder.Record(cleaner.SubscriptionChange{RunID: "run-1", ThreadID: "1", Action: cleaner.ActionIgnore}))
+	require.NoError(t, recorder.Record(cleaner.SubscriptionChange{
+		RunID:    "run-2",
+		ThreadID: "2",
+		Action:   cleaner.ActionUnsubscribe,
+		Previous: &cleaner.SubscriptionState{Subscribed: true},
+	}))
+
+	changes, err := cleaner.ReadJournal(p)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Nil(t, changes[0].Previous)
+	assert.Equal(t, &cleaner.SubscriptionState{Subscribed: true}, changes[1].Previous)
+	assert.Equal(t, []string{"run-1", "run-2"}, cleaner.Runs(changes))
+
+	_, err = cleaner.ReadJournal(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.Error(t, err)
+}
@@ -0,0 +1,72 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-github/v69/github"
+)
+
+// ReleaseRule marks release notifications as done based on the release details.
+// A release matches when any of the enabled conditions is true.
+type ReleaseRule struct {
+	// Prereleases marks prereleases as done.
+	Prereleases bool
+	// Drafts marks draft releases as done.
+	Drafts bool
+	// MaxAge marks releases published longer than MaxAge ago as done. Disabled when zero.
+	MaxAge time.Duration
+	// KeepOnlyMajorMinor marks patch releases (for example v1.2.3, but not v1.2.0) as done.
+	// Releases whose tag is not a semantic version are never considered patch releases.
+	KeepOnlyMajorMinor bool
+}
+
+// Name returns the rule name.
+func (r *ReleaseRule) Name() string {
+	return "release"
+}
+
+// Evaluate checks the release related to the notification.
+func (r *ReleaseRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	if t.GetSubject().GetType() != TypeRelease {
+		return Skip(), nil
+	}
+
+	release, err := t.Release(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+
+	tag := release.GetTagName()
+	switch {
+	case r.Prereleases && release.GetPrerelease():
+		return Done(fmt.Sprintf("release %s is a prerelease", tag)), nil
+	case r.Drafts && release.GetDraft():
+		return Done(fmt.Sprintf("release %s is a draft", tag)), nil
+	case r.isOld(release):
+		return Done(fmt.Sprintf("release %s was published more than %s ago", tag, r.MaxAge)), nil
+	case r.KeepOnlyMajorMinor && isPatchVersion(tag):
+		return Done(fmt.Sprintf("release %s is a patch release", tag)), nil
+	}
+
+	return Skip(), nil
+}
+
+// isOld reports whether the release was published longer than MaxAge ago, when MaxAge is set.
+func (r *ReleaseRule) isOld(release *github.RepositoryRelease) bool {
+	if r.MaxAge <= 0 || release.PublishedAt == nil {
+		return false
+	}
+	return release.PublishedAt.Before(time.Now().Add(-r.MaxAge))
+}
+
+// isPatchVersion reports whether the tag is a semantic version with a non-zero patch number.
+func isPatchVersion(tag string) bool {
+	v, err := semver.NewVersion(tag)
+	if err != nil {
+		return false
+	}
+	return v.Patch() > 0
+}
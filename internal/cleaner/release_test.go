@@ -0,0 +1,102 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestReleaseRule(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rule     *cleaner.ReleaseRule
+		release  map[string]any
+		expected cleaner.Outcome
+	}{
+		{
+			name:     "marks prereleases as done",
+			rule:     &cleaner.ReleaseRule{Prereleases: true},
+			release:  map[string]any{"tag_name": "v2.0.0-rc.1", "prerelease": true},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name:     "marks drafts as done",
+			rule:     &cleaner.ReleaseRule{Drafts: true},
+			release:  map[string]any{"tag_name": "v2.0.0", "draft": true},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name:     "marks old releases as done",
+			rule:     &cleaner.ReleaseRule{MaxAge: 12 * time.Hour},
+			release:  map[string]any{"tag_name": "v2.0.0", "published_at": time.Now().Add(-24 * time.Hour)},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name:     "does not mark recent releases as done",
+			rule:     &cleaner.ReleaseRule{MaxAge: 12 * time.Hour},
+			release:  map[string]any{"tag_name": "v2.0.0", "published_at": time.Now()},
+			expected: cleaner.OutcomeSkip,
+		},
+		{
+			name:     "marks patch releases as done",
+			rule:     &cleaner.ReleaseRule{KeepOnlyMajorMinor: true},
+			release:  map[string]any{"tag_name": "v1.2.3"},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name:     "keeps minor releases",
+			rule:     &cleaner.ReleaseRule{KeepOnlyMajorMinor: true},
+			release:  map[string]any{"tag_name": "1.3.0"},
+			expected: cleaner.OutcomeSkip,
+		},
+		{
+			name:     "keeps releases without semantic version",
+			rule:     &cleaner.ReleaseRule{KeepOnlyMajorMinor: true},
+			release:  map[string]any{"tag_name": "nightly"},
+			expected: cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/repos/owner/repo/releases/42").
+				Reply(200).
+				JSON(tc.release)
+
+			n := &github.Notification{
+				ID: github.Ptr("1"),
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypeRelease),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/releases/42"),
+				},
+			}
+
+			decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.True(t, gock.IsDone())
+		})
+	}
+
+	t.Run("skips other subject types", func(t *testing.T) {
+		n := &github.Notification{
+			ID:      github.Ptr("1"),
+			Subject: &github.NotificationSubject{Type: github.Ptr(cleaner.TypeIssue)},
+		}
+
+		rule := &cleaner.ReleaseRule{Prereleases: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(nil, n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+	})
+}
@@ -0,0 +1,49 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// RepoRule applies an outcome to notifications of the repositories matching one of the given patterns.
+// For example, it can keep every notification of the repositories in an on-call list, whatever their age.
+// Patterns use the syntax of path.Match and are matched against the repository full name (owner/repo).
+type RepoRule struct {
+	Patterns []string
+	// MaxAge restricts the rule to notifications not updated for longer than MaxAge.
+	// When zero, the rule applies regardless of the notification age.
+	MaxAge  time.Duration
+	Outcome Outcome
+}
+
+// NewRepoRule creates a new RepoRule.
+func NewRepoRule(patterns []string, maxAge time.Duration, outcome Outcome) *RepoRule {
+	return &RepoRule{
+		Patterns: patterns,
+		MaxAge:   maxAge,
+		Outcome:  outcome,
+	}
+}
+
+// Name returns the rule name.
+func (r *RepoRule) Name() string {
+	return "repo"
+}
+
+// Evaluate checks the notification repository and age.
+func (r *RepoRule) Evaluate(_ context.Context, t *Thread) (Decision, error) {
+	repo := t.GetRepository().GetFullName()
+	if !matchAny(r.Patterns, repo) {
+		return Skip(), nil
+	}
+
+	if r.MaxAge > 0 {
+		if !olderThan(t, r.MaxAge) {
+			return Skip(), nil
+		}
+		return Decision{Outcome: r.Outcome, Reason: fmt.Sprintf("repository is %s and not updated for more than %s", repo, r.MaxAge)}, nil
+	}
+
+	return Decision{Outcome: r.Outcome, Reason: fmt.Sprintf("repository is %s", repo)}, nil
+}
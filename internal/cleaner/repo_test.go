@@ -0,0 +1,65 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestRepoRule(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rule      *cleaner.RepoRule
+		repo      string
+		updatedAt time.Time
+		expected  cleaner.Outcome
+	}{
+		{
+			name:      "keeps matching repositories regardless of age",
+			rule:      cleaner.NewRepoRule([]string{"my-org/on-call-*"}, 0, cleaner.OutcomeKeep),
+			repo:      "my-org/on-call-api",
+			updatedAt: time.Now().AddDate(-1, 0, 0),
+			expected:  cleaner.OutcomeKeep,
+		},
+		{
+			name:      "marks old notifications of matching repositories as done",
+			rule:      cleaner.NewRepoRule([]string{"my-org/sandbox"}, 24*time.Hour, cleaner.OutcomeDone),
+			repo:      "my-org/sandbox",
+			updatedAt: time.Now().Add(-48 * time.Hour),
+			expected:  cleaner.OutcomeDone,
+		},
+		{
+			name:      "skips recent notifications of matching repositories",
+			rule:      cleaner.NewRepoRule([]string{"my-org/sandbox"}, 24*time.Hour, cleaner.OutcomeDone),
+			repo:      "my-org/sandbox",
+			updatedAt: time.Now(),
+			expected:  cleaner.OutcomeSkip,
+		},
+		{
+			name:      "skips other repositories",
+			rule:      cleaner.NewRepoRule([]string{"my-org/on-call-*"}, 0, cleaner.OutcomeKeep),
+			repo:      "my-org/website",
+			updatedAt: time.Now(),
+			expected:  cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &github.Notification{
+				ID:         github.Ptr("1"),
+				UpdatedAt:  &github.Timestamp{Time: tc.updatedAt},
+				Repository: &github.Repository{FullName: github.Ptr(tc.repo)},
+			}
+			decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(nil, n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// threadSubscription returns the subscription of the thread, or nil when there is none.
+func threadSubscription(ctx context.Context, client *github.Client, id string) (*SubscriptionState, error) {
+	sub, resp, err := client.Activity.GetThreadSubscription(ctx, id)
+	if isNotFound(resp) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching subscription of thread %s: %w", id, err)
+	}
+	return &SubscriptionState{Subscribed: sub.GetSubscribed(), Ignored: sub.GetIgnored()}, nil
+}
+
+// repositorySubscription returns the subscription of the repository, or nil when there is none.
+func repositorySubscription(ctx context.Context, client *github.Client, repo string) (*SubscriptionState, error) {
+	owner, name := splitRepository(repo)
+	sub, resp, err := client.Activity.GetRepositorySubscription(ctx, owner, name)
+	if isNotFound(resp) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching subscription of repository %s: %w", repo, err)
+	}
+	return &SubscriptionState{Subscribed: sub.GetSubscribed(), Ignored: sub.GetIgnored()}, nil
+}
+
+// Runs returns the IDs of the runs with changes in the journal, in recording order.
+func Runs(changes []SubscriptionChange) []string {
+	var runs []string
+	for _, c := range changes {
+		if c.RunID != "" && !slices.Contains(runs, c.RunID) {
+			runs = append(runs, c.RunID)
+		}
+	}
+	return runs
+}
+
+// Restore reverts the subscription changes of the given run, from the last to the first one,
+// by setting back the previous subscription state. Marking threads as done cannot be reverted.
+// It returns the number of reverted changes. Errors do not stop the restoration of the other changes.
+func Restore(ctx context.Context, client *github.Client, changes []SubscriptionChange, runID string, dryRun bool) (int, error) {
+	var errs []error
+	restored := 0
+	for _, change := range slices.Backward(changes) {
+		if change.RunID != runID {
+			continue
+		}
+
+		slog.Info("restoring subscription",
+			slog.String("run_id", change.RunID),
+			slog.String("notification_id", change.ThreadID),
+			slog.String("repository", change.Repository),
+			slog.String("subject", change.Subject),
+			slog.String("action", string(change.Action)),
+		)
+		if dryRun {
+			restored++
+			continue
+		}
+
+		if err := restoreChange(ctx, client, change); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		restored++
+	}
+
+	return restored, errors.Join(errs...)
+}
+
+// restoreChange sets back the subscription state before the change.
+func restoreChange(ctx context.Context, client *github.Client, change SubscriptionChange) error {
+	prev := change.Previous
+
+	if change.ThreadID == "" {
+		owner, name := splitRepository(change.Repository)
+		if prev == nil {
+			if _, err := client.Activity.DeleteRepositorySubscription(ctx, owner, name); err != nil {
+				return fmt.Errorf("error restoring subscription of repository %s: %w", change.Repository, err)
+			}
+			return nil
+		}
+
+		sub := &github.Subscription{Subscribed: github.Ptr(prev.Subscribed), Ignored: github.Ptr(prev.Ignored)}
+		if _, _, err := client.Activity.SetRepositorySubscription(ctx, owner, name, sub); err != nil {
+			return fmt.Errorf("error restoring subscription of repository %s: %w", change.Repository, err)
+		}
+		return nil
+	}
+
+	if prev == nil {
+		if _, err := client.Activity.DeleteThreadSubscription(ctx, change.ThreadID); err != nil {
+			return fmt.Errorf("error restoring subscription of thread %s: %w", change.ThreadID, err)
+		}
+		return nil
+	}
+
+	// Thread subscriptions can only be set to ignored or not.
+	sub := &github.Subscription{Ignored: github.Ptr(prev.Ignored)}
+	if _, _, err := client.Activity.SetThreadSubscription(ctx, change.ThreadID, sub); err != nil {
+		return fmt.Errorf("error restoring subscription of thread %s: %w", change.ThreadID, err)
+	}
+	return nil
+}
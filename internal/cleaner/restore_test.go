@@ -0,0 +1,82 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestRestore(t *testing.T) {
+	changes := []cleaner.SubscriptionChange{
+		{RunID: "run-1", ThreadID: "1", Action: cleaner.ActionIgnore, Previous: &cleaner.SubscriptionState{Subscribed: true}},
+		{RunID: "run-2", ThreadID: "2", Action: cleaner.ActionUnsubscribe, Previous: &cleaner.SubscriptionState{Subscribed: true}},
+		{RunID: "run-2", ThreadID: "3", Action: cleaner.ActionIgnore},
+		{RunID: "run-2", Repository: "owner/noisy", Action: cleaner.ActionIgnoreRepository, Previous: &cleaner.SubscriptionState{Subscribed: true}},
+		{RunID: "run-2", Repository: "owner/quiet", Action: cleaner.ActionIgnoreRepository},
+	}
+
+	t.Run("restores the previous subscriptions of the run", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Delete("/repos/owner/quiet/subscription").
+			Reply(204)
+		gock.New("https://api.github.com").
+			Put("/repos/owner/noisy/subscription").
+			JSON(map[string]any{"subscribed": true, "ignored": false}).
+			Reply(200).
+			JSON(map[string]any{"subscribed": true})
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/3/subscription").
+			Reply(204)
+		gock.New("https://api.github.com").
+			Put("/notifications/threads/2/subscription").
+			JSON(map[string]any{"ignored": false}).
+			Reply(200).
+			JSON(map[string]any{"subscribed": true})
+
+		restored, err := cleaner.Restore(context.Background(), setupMockClient(t), changes, "run-2", false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, restored)
+		assert.True(t, gock.IsDone())
+		assert.False(t, gock.HasUnmatchedRequest(), "expected changes of other runs to be left untouched")
+	})
+
+	t.Run("continues after errors", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Delete("/repos/owner/quiet/subscription").
+			Reply(500)
+		gock.New("https://api.github.com").
+			Put("/repos/owner/noisy/subscription").
+			Reply(200).
+			JSON(map[string]any{"subscribed": true})
+		gock.New("https://api.github.com").
+			Delete("/notifications/threads/3/subscription").
+			Reply(204)
+		gock.New("https://api.github.com").
+			Put("/notifications/threads/2/subscription").
+			Reply(200).
+			JSON(map[string]any{"subscribed": true})
+
+		restored, err := cleaner.Restore(context.Background(), setupMockClient(t), changes, "run-2", false)
+		require.Error(t, err)
+		assert.Equal(t, 3, restored)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("does not change subscriptions in dry-run mode", func(t *testing.T) {
+		defer gock.Off()
+
+		restored, err := cleaner.Restore(context.Background(), setupMockClient(t), changes, "run-1", true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, restored)
+		assert.False(t, gock.HasUnmatchedRequest())
+	})
+}
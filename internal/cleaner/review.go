@@ -0,0 +1,107 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ReviewRequestRule marks review_requested notifications as done when the review
+// request is no longer pending: the request to the authenticated user (or one of
+// their teams) was removed, or the user already submitted a review.
+type ReviewRequestRule struct{}
+
+// NewReviewRequestRule creates a new ReviewRequestRule.
+func NewReviewRequestRule() *ReviewRequestRule {
+	return &ReviewRequestRule{}
+}
+
+// Name returns the rule name.
+func (r *ReviewRequestRule) Name() string {
+	return "review_request"
+}
+
+// Evaluate checks if the review request related to the notification is still pending.
+func (r *ReviewRequestRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	if t.GetReason() != ReasonReviewRequested || t.GetSubject().GetType() != TypePullRequest {
+		return Skip(), nil
+	}
+
+	pr, err := t.PullRequest(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+
+	login, err := t.Viewer().Login(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+
+	// Re-requesting a review adds the user back to the requested reviewers,
+	// so a pending personal request always wins over past reviews.
+	if slices.ContainsFunc(pr.RequestedReviewers, func(u *github.User) bool { return u.GetLogin() == login }) {
+		return Skip(), nil
+	}
+
+	reviewed, err := r.hasReviewed(ctx, t, login)
+	if err != nil {
+		return Skip(), err
+	}
+	if reviewed {
+		return Done(fmt.Sprintf("already reviewed pull request #%d", pr.GetNumber())), nil
+	}
+
+	requested, err := r.inRequestedTeams(ctx, t, pr)
+	if err != nil {
+		return Skip(), err
+	}
+	if requested {
+		return Skip(), nil
+	}
+
+	return Done(fmt.Sprintf("review request for pull request #%d was removed", pr.GetNumber())), nil
+}
+
+// inRequestedTeams reports whether the user is a member of one of the teams requested to review the pull request.
+func (r *ReviewRequestRule) inRequestedTeams(ctx context.Context, t *Thread, pr *github.PullRequest) (bool, error) {
+	org := pr.GetBase().GetRepo().GetOwner().GetLogin()
+	for _, team := range pr.RequestedTeams {
+		member, err := t.Viewer().InTeam(ctx, org, team.GetSlug())
+		if err != nil {
+			return false, err
+		}
+		if member {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hasReviewed reports whether the user submitted a review on the pull request.
+func (r *ReviewRequestRule) hasReviewed(ctx context.Context, t *Thread, login string) (bool, error) {
+	owner, repo, number, err := parseNotificationURL(t.GetSubject().GetURL())
+	if err != nil {
+		return false, fmt.Errorf("error parsing notification URL for notification %s: %w", t.GetID(), err)
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		reviews, resp, err := t.client.PullRequests.ListReviews(ctx, owner, repo, number, opts)
+		if err != nil {
+			return false, fmt.Errorf("error listing reviews of pull request %s/%s#%d: %w", owner, repo, number, err)
+		}
+
+		for _, review := range reviews {
+			if review.GetUser().GetLogin() == login && review.GetState() != "PENDING" {
+				return true, nil
+			}
+		}
+
+		if resp.NextPage == 0 {
+			return false, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
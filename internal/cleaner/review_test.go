@@ -0,0 +1,125 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestReviewRequestRule(t *testing.T) {
+	base := map[string]any{"repo": map[string]any{"owner": map[string]any{"login": "owner"}}}
+
+	testCases := []struct {
+		name     string
+		pr       map[string]any
+		reviews  []map[string]any
+		teams    []map[string]any
+		expected cleaner.Outcome
+	}{
+		{
+			name: "skips pending personal review requests",
+			pr: map[string]any{
+				"number":              1,
+				"base":                base,
+				"requested_reviewers": []map[string]any{{"login": "me"}},
+			},
+			expected: cleaner.OutcomeSkip,
+		},
+		{
+			name:     "marks already reviewed pull requests as done",
+			pr:       map[string]any{"number": 1, "base": base},
+			reviews:  []map[string]any{{"state": "APPROVED", "user": map[string]any{"login": "me"}}},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name:     "marks removed review requests as done",
+			pr:       map[string]any{"number": 1, "base": base},
+			reviews:  []map[string]any{{"state": "APPROVED", "user": map[string]any{"login": "teammate"}}},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name: "skips pending team review requests",
+			pr: map[string]any{
+				"number":          1,
+				"base":            base,
+				"requested_teams": []map[string]any{{"slug": "reviewers"}},
+			},
+			teams:    []map[string]any{{"slug": "reviewers", "organization": map[string]any{"login": "owner"}}},
+			expected: cleaner.OutcomeSkip,
+		},
+		{
+			name: "marks review requests to other teams as done",
+			pr: map[string]any{
+				"number":          1,
+				"base":            base,
+				"requested_teams": []map[string]any{{"slug": "reviewers"}},
+			},
+			teams:    []map[string]any{{"slug": "reviewers", "organization": map[string]any{"login": "other"}}},
+			expected: cleaner.OutcomeDone,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get("/repos/owner/repo/pulls/1").
+				Reply(200).
+				JSON(tc.pr)
+
+			gock.New("https://api.github.com").
+				Get("/user").
+				Reply(200).
+				JSON(map[string]any{"login": "me"})
+
+			if _, requested := tc.pr["requested_reviewers"]; !requested {
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/pulls/1/reviews").
+					Reply(200).
+					JSON(tc.reviews)
+			}
+
+			if tc.teams != nil {
+				gock.New("https://api.github.com").
+					Get("/user/teams").
+					Reply(200).
+					JSON(tc.teams)
+			}
+
+			n := &github.Notification{
+				ID:     github.Ptr("1"),
+				Reason: github.Ptr(cleaner.ReasonReviewRequested),
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypePullRequest),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/pulls/1"),
+				},
+			}
+
+			decision, err := cleaner.NewReviewRequestRule().Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.True(t, gock.IsDone())
+		})
+	}
+
+	t.Run("skips other reasons", func(t *testing.T) {
+		n := &github.Notification{
+			ID:     github.Ptr("1"),
+			Reason: github.Ptr(cleaner.ReasonSubscribed),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypePullRequest),
+			},
+		}
+
+		decision, err := cleaner.NewReviewRequestRule().Evaluate(context.Background(), cleaner.NewThread(nil, n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+	})
+}
@@ -0,0 +1,282 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"time"
+)
+
+// Outcome defines what should happen to a notification after a rule is evaluated.
+type Outcome int
+
+const (
+	// OutcomeSkip means the rule does not apply and evaluation continues with the next rule.
+	OutcomeSkip Outcome = iota
+	// OutcomeDone means the notification should be cleaned: marked as done, or given the actions of the decision.
+	OutcomeDone
+	// OutcomeKeep means the notification should be left untouched, regardless of the remaining rules.
+	OutcomeKeep
+)
+
+// String returns the string representation of the outcome.
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSkip:
+		return "skip"
+	case OutcomeDone:
+		return "done"
+	case OutcomeKeep:
+		return "keep"
+	default:
+		return fmt.Sprintf("Outcome(%d)", int(o))
+	}
+}
+
+// ParseOutcome parses the string representation of an outcome other than OutcomeSkip.
+func ParseOutcome(s string) (Outcome, error) {
+	switch s {
+	case "done":
+		return OutcomeDone, nil
+	case "keep":
+		return OutcomeKeep, nil
+	default:
+		return OutcomeSkip, fmt.Errorf("unknown outcome %q", s)
+	}
+}
+
+// Decision is the result of evaluating a Rule against a notification.
+type Decision struct {
+	Outcome Outcome
+	// Reason is a short human readable explanation of the decision, used for logging.
+	Reason string
+	// Attrs are additional details about the decision, such as the applied threshold,
+	// included in the logs. They are reported even when the outcome is OutcomeSkip.
+	Attrs []slog.Attr
+	// Actions are applied to the notification when the outcome is OutcomeDone.
+	// When empty, the notification is marked as done.
+	Actions []Action
+}
+
+// Skip returns a decision that lets the evaluation continue with the next rule.
+func Skip() Decision {
+	return Decision{Outcome: OutcomeSkip}
+}
+
+// Done returns a decision to mark the notification as done.
+func Done(reason string) Decision {
+	return Decision{Outcome: OutcomeDone, Reason: reason}
+}
+
+// Keep returns a decision to leave the notification untouched.
+func Keep(reason string) Decision {
+	return Decision{Outcome: OutcomeKeep, Reason: reason}
+}
+
+// Rule decides what should happen to a notification.
+// Rules are evaluated in order and the first one returning a decision other than
+// OutcomeSkip wins.
+type Rule interface {
+	// Name returns the rule name, used for logging.
+	Name() string
+	// Evaluate returns the decision of the rule for the given notification thread.
+	Evaluate(ctx context.Context, t *Thread) (Decision, error)
+}
+
+// DefaultRules returns the built-in rules used when no rules are configured.
+// The security alert rule comes first, so that open alerts are never cleaned by age.
+func DefaultRules(olderThan time.Duration) []Rule {
+	return []Rule{
+		NewSecurityAlertRule(),
+		NewAgeRule(olderThan),
+		NewClosedSubjectRule(),
+	}
+}
+
+// Timestamp identifies the notification or subject timestamp an age is computed from.
+type Timestamp string
+
+// Supported timestamps.
+const (
+	// TimestampUpdatedAt is when the notification was last updated.
+	TimestampUpdatedAt Timestamp = "updated_at"
+	// TimestampLastReadAt is when the notification was last read.
+	TimestampLastReadAt Timestamp = "last_read_at"
+	// TimestampSubjectUpdatedAt is when the related issue or pull request was last updated.
+	TimestampSubjectUpdatedAt Timestamp = "subject.updated_at"
+	// TimestampSubjectClosedAt is when the related issue or pull request was closed.
+	TimestampSubjectClosedAt Timestamp = "subject.closed_at"
+)
+
+// Timestamps lists all the supported timestamps.
+var Timestamps = []Timestamp{TimestampUpdatedAt, TimestampLastReadAt, TimestampSubjectUpdatedAt, TimestampSubjectClosedAt}
+
+// AgeOverride overrides the maximum age of the AgeRule for the repositories matching Pattern.
+// Pattern uses the syntax of path.Match and is matched against the repository full name (owner/repo).
+type AgeOverride struct {
+	Pattern string
+	MaxAge  time.Duration
+}
+
+// AgeRule marks notifications as done when they were not updated for longer than MaxAge.
+type AgeRule struct {
+	MaxAge time.Duration
+	// Overrides set a different maximum age for some repositories.
+	// When several overrides match a repository, the most specific pattern wins.
+	Overrides []AgeOverride
+	// Timestamp is the timestamp the age is computed from. Defaults to TimestampUpdatedAt.
+	Timestamp Timestamp
+}
+
+// NewAgeRule creates a new AgeRule with the given maximum age and per repository overrides.
+func NewAgeRule(maxAge time.Duration, overrides ...AgeOverride) *AgeRule {
+	return &AgeRule{MaxAge: maxAge, Overrides: overrides}
+}
+
+// Name returns the rule name.
+func (r *AgeRule) Name() string {
+	return "age"
+}
+
+// Evaluate checks if the notification is older than the maximum age applicable to its repository.
+func (r *AgeRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	maxAge, pattern := r.threshold(t.GetRepository().GetFullName())
+	attrs := []slog.Attr{slog.Duration("threshold", maxAge)}
+	if pattern != "" {
+		attrs = append(attrs, slog.String("threshold_pattern", pattern))
+	}
+
+	timestamp := r.Timestamp
+	if timestamp == "" {
+		timestamp = TimestampUpdatedAt
+	}
+
+	at, err := t.Timestamp(ctx, timestamp)
+	if err != nil {
+		return Skip(), err
+	}
+
+	if at.IsZero() || !at.Before(time.Now().Add(-maxAge)) {
+		return Decision{Outcome: OutcomeSkip, Attrs: attrs}, nil
+	}
+
+	reason := fmt.Sprintf("not updated for more than %s", maxAge)
+	if timestamp != TimestampUpdatedAt {
+		reason = fmt.Sprintf("%s is more than %s ago", timestamp, maxAge)
+	}
+	return Decision{
+		Outcome: OutcomeDone,
+		Reason:  reason,
+		Attrs:   attrs,
+	}, nil
+}
+
+// threshold returns the maximum age applicable to the given repository,
+// along with the pattern of the matching override, if any.
+// The most specific override wins, see patternSpecificity.
+// On ties, the first declared override wins.
+func (r *AgeRule) threshold(repo string) (time.Duration, string) {
+	maxAge, pattern, best := r.MaxAge, "", -1
+	for _, o := range r.Overrides {
+		if ok, err := path.Match(o.Pattern, repo); err != nil || !ok {
+			continue
+		}
+
+		if specificity := patternSpecificity(o.Pattern); specificity > best {
+			maxAge, pattern, best = o.MaxAge, o.Pattern, specificity
+		}
+	}
+	return maxAge, pattern
+}
+
+// olderThan reports whether the notification was not updated for longer than maxAge.
+func olderThan(t *Thread, maxAge time.Duration) bool {
+	return t.UpdatedAt != nil && t.UpdatedAt.Before(time.Now().Add(-maxAge))
+}
+
+// patternSpecificity returns how specific a glob pattern is, as the number of literal characters in it.
+// For example, "owner/repo" is more specific than "owner/repo-*", which is more specific than "owner/*".
+func patternSpecificity(pattern string) int {
+	specificity := 0
+	inClass := false
+	for _, c := range pattern {
+		switch {
+		case c == '[':
+			inClass = true
+		case c == ']':
+			inClass = false
+		case inClass || c == '*' || c == '?':
+		default:
+			specificity++
+		}
+	}
+	return specificity
+}
+
+// ClosedState describes how an issue or pull request was closed.
+type ClosedState string
+
+// Supported closed states.
+const (
+	// StateMerged is a merged pull request.
+	StateMerged ClosedState = "merged"
+	// StateClosedUnmerged is a pull request closed without being merged.
+	StateClosedUnmerged ClosedState = "closed_unmerged"
+	// StateCompleted is an issue closed as completed.
+	StateCompleted ClosedState = "completed"
+	// StateNotPlanned is an issue closed as not planned.
+	StateNotPlanned ClosedState = "not_planned"
+	// StateDuplicate is an issue closed as a duplicate.
+	StateDuplicate ClosedState = "duplicate"
+)
+
+// ClosedStates lists all the supported closed states.
+var ClosedStates = []ClosedState{StateMerged, StateClosedUnmerged, StateCompleted, StateNotPlanned, StateDuplicate}
+
+// ClosedSubjectRule marks notifications as done when the related issue or pull request is closed.
+type ClosedSubjectRule struct {
+	// States restricts the rule to subjects closed in one of the given states.
+	// When empty, any closed subject matches.
+	States []ClosedState
+	// Ignore also sets the subscription of the matching threads to ignored,
+	// so that later comments on the closed subject do not notify again.
+	Ignore bool
+}
+
+// NewClosedSubjectRule creates a new ClosedSubjectRule matching the given closed states.
+func NewClosedSubjectRule(states ...ClosedState) *ClosedSubjectRule {
+	return &ClosedSubjectRule{States: states}
+}
+
+// Name returns the rule name.
+func (r *ClosedSubjectRule) Name() string {
+	return "closed"
+}
+
+// Evaluate checks if the issue or pull request related to the notification is closed
+// in one of the configured states.
+func (r *ClosedSubjectRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	state, err := t.ClosedState(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+
+	if state == "" {
+		return Skip(), nil
+	}
+
+	if len(r.States) > 0 && !slices.Contains(r.States, state) {
+		return Skip(), nil
+	}
+
+	decision := Done(fmt.Sprintf("issue is closed (%s)", state))
+	if t.GetSubject().GetType() == TypePullRequest {
+		decision = Done(fmt.Sprintf("pull request is closed (%s)", state))
+	}
+	if r.Ignore {
+		decision.Actions = []Action{ActionIgnore, ActionDone}
+	}
+	return decision, nil
+}
@@ -0,0 +1,291 @@
+package cleaner_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestAgeRule(t *testing.T) {
+	rule := cleaner.NewAgeRule(24 * time.Hour)
+
+	testCases := []struct {
+		name      string
+		updatedAt *github.Timestamp
+		expected  cleaner.Outcome
+	}{
+		{"old notification", &github.Timestamp{Time: time.Now().Add(-48 * time.Hour)}, cleaner.OutcomeDone},
+		{"recent notification", &github.Timestamp{Time: time.Now()}, cleaner.OutcomeSkip},
+		{"missing update date", nil, cleaner.OutcomeSkip},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &github.Notification{ID: github.Ptr("1"), UpdatedAt: tc.updatedAt}
+			decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(nil, n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+		})
+	}
+}
+
+func TestAgeRule_Overrides(t *testing.T) {
+	rule := cleaner.NewAgeRule(30*24*time.Hour,
+		cleaner.AgeOverride{Pattern: "*/*", MaxAge: 10 * 24 * time.Hour},
+		cleaner.AgeOverride{Pattern: "my-org/*", MaxAge: 60 * 24 * time.Hour},
+		cleaner.AgeOverride{Pattern: "my-org/noisy", MaxAge: 3 * 24 * time.Hour},
+	)
+
+	testCases := []struct {
+		name     string
+		repo     string
+		age      time.Duration
+		expected cleaner.Outcome
+		pattern  string
+	}{
+		{"most specific pattern wins over wildcard", "my-org/repo", 20 * 24 * time.Hour, cleaner.OutcomeSkip, "my-org/*"},
+		{"exact pattern wins", "my-org/noisy", 5 * 24 * time.Hour, cleaner.OutcomeDone, "my-org/noisy"},
+		{"generic pattern applies to other repositories", "other/repo", 15 * 24 * time.Hour, cleaner.OutcomeDone, "*/*"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &github.Notification{
+				ID:         github.Ptr("1"),
+				UpdatedAt:  &github.Timestamp{Time: time.Now().Add(-tc.age)},
+				Repository: &github.Repository{FullName: github.Ptr(tc.repo)},
+			}
+			decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(nil, n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.Contains(t, decision.Attrs, slog.String("threshold_pattern", tc.pattern))
+		})
+	}
+}
+
+func TestAgeRule_Timestamp(t *testing.T) {
+	old := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339)
+	recent := time.Now().UTC().Format(time.RFC3339)
+
+	testCases := []struct {
+		name      string
+		timestamp cleaner.Timestamp
+		issue     map[string]any
+		expected  cleaner.Outcome
+	}{
+		{"old last read date", cleaner.TimestampLastReadAt, nil, cleaner.OutcomeDone},
+		{"recent subject update", cleaner.TimestampSubjectUpdatedAt, map[string]any{"updated_at": recent}, cleaner.OutcomeSkip},
+		{"old subject closing date", cleaner.TimestampSubjectClosedAt, map[string]any{"updated_at": recent, "closed_at": old}, cleaner.OutcomeDone},
+		{"open subject", cleaner.TimestampSubjectClosedAt, map[string]any{"updated_at": old}, cleaner.OutcomeSkip},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			if tc.issue != nil {
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/issues/1").
+					Reply(200).
+					JSON(tc.issue)
+			}
+
+			n := &github.Notification{
+				ID:         github.Ptr("1"),
+				UpdatedAt:  &github.Timestamp{Time: time.Now()},
+				LastReadAt: &github.Timestamp{Time: time.Now().Add(-48 * time.Hour)},
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypeIssue),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/issues/1"),
+				},
+			}
+
+			rule := &cleaner.AgeRule{MaxAge: 24 * time.Hour, Timestamp: tc.timestamp}
+			decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
+
+func TestClosedSubjectRule(t *testing.T) {
+	testCases := []struct {
+		name        string
+		subjectType string
+		path        string
+		response    map[string]any
+		states      []cleaner.ClosedState
+		expected    cleaner.Outcome
+	}{
+		{
+			name:        "merged pull request matches merged state",
+			subjectType: cleaner.TypePullRequest,
+			path:        "/repos/owner/repo/pulls/1",
+			response:    map[string]any{"state": "closed", "merged": true},
+			states:      []cleaner.ClosedState{cleaner.StateMerged},
+			expected:    cleaner.OutcomeDone,
+		},
+		{
+			name:        "unmerged pull request does not match merged state",
+			subjectType: cleaner.TypePullRequest,
+			path:        "/repos/owner/repo/pulls/1",
+			response:    map[string]any{"state": "closed", "merged": false},
+			states:      []cleaner.ClosedState{cleaner.StateMerged},
+			expected:    cleaner.OutcomeSkip,
+		},
+		{
+			name:        "unmerged pull request matches closed_unmerged state",
+			subjectType: cleaner.TypePullRequest,
+			path:        "/repos/owner/repo/pulls/1",
+			response:    map[string]any{"state": "closed", "merged": false},
+			states:      []cleaner.ClosedState{cleaner.StateClosedUnmerged},
+			expected:    cleaner.OutcomeDone,
+		},
+		{
+			name:        "issue closed as not planned does not match completed state",
+			subjectType: cleaner.TypeIssue,
+			path:        "/repos/owner/repo/issues/1",
+			response:    map[string]any{"state": "closed", "state_reason": "not_planned"},
+			states:      []cleaner.ClosedState{cleaner.StateCompleted},
+			expected:    cleaner.OutcomeSkip,
+		},
+		{
+			name:        "issue closed as duplicate matches duplicate state",
+			subjectType: cleaner.TypeIssue,
+			path:        "/repos/owner/repo/issues/1",
+			response:    map[string]any{"state": "closed", "state_reason": "duplicate"},
+			states:      []cleaner.ClosedState{cleaner.StateDuplicate},
+			expected:    cleaner.OutcomeDone,
+		},
+		{
+			name:        "issue closed without reason is completed",
+			subjectType: cleaner.TypeIssue,
+			path:        "/repos/owner/repo/issues/1",
+			response:    map[string]any{"state": "closed"},
+			states:      []cleaner.ClosedState{cleaner.StateCompleted},
+			expected:    cleaner.OutcomeDone,
+		},
+		{
+			name:        "any closed state matches without states",
+			subjectType: cleaner.TypeIssue,
+			path:        "/repos/owner/repo/issues/1",
+			response:    map[string]any{"state": "closed", "state_reason": "not_planned"},
+			expected:    cleaner.OutcomeDone,
+		},
+		{
+			name:        "open issue does not match",
+			subjectType: cleaner.TypeIssue,
+			path:        "/repos/owner/repo/issues/1",
+			response:    map[string]any{"state": "open"},
+			expected:    cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			gock.New("https://api.github.com").
+				Get(tc.path).
+				Reply(200).
+				JSON(tc.response)
+
+			n := &github.Notification{
+				ID: github.Ptr("1"),
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(tc.subjectType),
+					URL:  github.Ptr("https://api.github.com" + tc.path),
+				},
+			}
+
+			rule := cleaner.NewClosedSubjectRule(tc.states...)
+			decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.True(t, gock.IsDone())
+		})
+	}
+	t.Run("ignores the thread when enabled", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/pulls/1").
+			Reply(200).
+			JSON(map[string]any{"state": "closed", "merged": true})
+
+		n := &github.Notification{
+			ID: github.Ptr("1"),
+			Subject: &github.NotificationSubject{
+				Type: github.Ptr(cleaner.TypePullRequest),
+				URL:  github.Ptr("https://api.github.com/repos/owner/repo/pulls/1"),
+			},
+		}
+
+		rule := &cleaner.ClosedSubjectRule{Ignore: true}
+		decision, err := rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeDone, decision.Outcome)
+		assert.Equal(t, []cleaner.Action{cleaner.ActionIgnore, cleaner.ActionDone}, decision.Actions)
+	})
+}
+
+func TestReasonRule(t *testing.T) {
+	testCases := []struct {
+		name      string
+		rule      *cleaner.ReasonRule
+		reason    string
+		updatedAt time.Time
+		expected  cleaner.Outcome
+	}{
+		{
+			name:      "keeps matching reason regardless of age",
+			rule:      cleaner.NewReasonRule([]string{cleaner.ReasonMention}, 0, cleaner.OutcomeKeep),
+			reason:    cleaner.ReasonMention,
+			updatedAt: time.Now().AddDate(-1, 0, 0),
+			expected:  cleaner.OutcomeKeep,
+		},
+		{
+			name:      "marks old matching reason as done",
+			rule:      cleaner.NewReasonRule([]string{cleaner.ReasonCIActivity}, 24*time.Hour, cleaner.OutcomeDone),
+			reason:    cleaner.ReasonCIActivity,
+			updatedAt: time.Now().Add(-48 * time.Hour),
+			expected:  cleaner.OutcomeDone,
+		},
+		{
+			name:      "skips recent matching reason",
+			rule:      cleaner.NewReasonRule([]string{cleaner.ReasonCIActivity}, 24*time.Hour, cleaner.OutcomeDone),
+			reason:    cleaner.ReasonCIActivity,
+			updatedAt: time.Now(),
+			expected:  cleaner.OutcomeSkip,
+		},
+		{
+			name:      "skips other reasons",
+			rule:      cleaner.NewReasonRule([]string{cleaner.ReasonMention}, 0, cleaner.OutcomeKeep),
+			reason:    cleaner.ReasonSubscribed,
+			updatedAt: time.Now(),
+			expected:  cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			n := &github.Notification{
+				ID:        github.Ptr("1"),
+				Reason:    github.Ptr(tc.reason),
+				UpdatedAt: &github.Timestamp{Time: tc.updatedAt},
+			}
+			decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(nil, n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// securityAlertPackageRegex extracts the package name from the title of security alert notifications,
+// for example "Potential security vulnerability found in the lodash dependency".
+var securityAlertPackageRegex = regexp.MustCompile(`(?i)\bin (?:the )?(\S+) dependency`)
+
+// SecurityAlertRule resolves security alert notifications through the Dependabot alerts API.
+// Notifications are marked as done once the related alerts are fixed or dismissed, and kept
+// while an alert is open, so that the following rules (for example the age rule) never clean them.
+//
+// When the package can be extracted from the notification title, only the alerts of that
+// package are considered. Otherwise, any open alert in the repository keeps the notification.
+// When the alerts cannot be accessed, their state is unknown and the notification is kept.
+type SecurityAlertRule struct{}
+
+// NewSecurityAlertRule creates a new SecurityAlertRule.
+func NewSecurityAlertRule() *SecurityAlertRule {
+	return &SecurityAlertRule{}
+}
+
+// Name returns the rule name.
+func (r *SecurityAlertRule) Name() string {
+	return "security_alert"
+}
+
+// Evaluate checks the Dependabot alerts related to the notification.
+func (r *SecurityAlertRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	switch t.GetSubject().GetType() {
+	case TypeRepositoryVulnerabilityAlert, TypeRepositoryDependabotAlertsThread:
+	default:
+		return Skip(), nil
+	}
+
+	owner, repo := splitRepository(t.GetRepository().GetFullName())
+	opts := &github.ListAlertsOptions{
+		State:       github.Ptr("open"),
+		ListOptions: github.ListOptions{PerPage: 1},
+	}
+
+	target := "repository"
+	if matches := securityAlertPackageRegex.FindStringSubmatch(t.GetSubject().GetTitle()); matches != nil {
+		opts.Package = github.Ptr(matches[1])
+		target = fmt.Sprintf("%s package", matches[1])
+	}
+
+	alerts, resp, err := t.client.Dependabot.ListRepoAlerts(ctx, owner, repo, opts)
+	if isRateLimited(err) {
+		return Skip(), fmt.Errorf("error listing dependabot alerts for %s/%s: %w", owner, repo, err)
+	}
+	if isNotFound(resp) || isForbidden(resp) {
+		// Alerts are not available without the security_events scope, admin access
+		// to the repository, or when they are disabled. An alert may still be open.
+		slog.Warn("dependabot alerts not available, keeping security alert notification",
+			slog.String("notification_id", t.GetID()),
+			slog.String("repository", t.GetRepository().GetFullName()),
+			slog.Int("status", resp.StatusCode),
+		)
+		return Keep("dependabot alerts are not accessible"), nil
+	}
+	if err != nil {
+		return Skip(), fmt.Errorf("error listing dependabot alerts for %s/%s: %w", owner, repo, err)
+	}
+
+	if len(alerts) > 0 {
+		return Keep(fmt.Sprintf("%s has open security alerts", target)), nil
+	}
+
+	return Done(fmt.Sprintf("%s has no open security alerts", target)), nil
+}
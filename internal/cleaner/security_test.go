@@ -0,0 +1,143 @@
+package cleaner_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestSecurityAlertRule(t *testing.T) {
+	testCases := []struct {
+		name        string
+		subjectType string
+		title       string
+		pkg         string
+		alerts      []map[string]any
+		expected    cleaner.Outcome
+	}{
+		{
+			name:        "keeps notifications with open alerts",
+			subjectType: cleaner.TypeRepositoryVulnerabilityAlert,
+			title:       "Potential security vulnerability found in the lodash dependency",
+			pkg:         "lodash",
+			alerts:      []map[string]any{{"number": 1, "state": "open"}},
+			expected:    cleaner.OutcomeKeep,
+		},
+		{
+			name:        "marks notifications without open alerts as done",
+			subjectType: cleaner.TypeRepositoryVulnerabilityAlert,
+			title:       "Potential security vulnerability found in the lodash dependency",
+			pkg:         "lodash",
+			alerts:      []map[string]any{},
+			expected:    cleaner.OutcomeDone,
+		},
+		{
+			name:        "checks all repository alerts without package",
+			subjectType: cleaner.TypeRepositoryDependabotAlertsThread,
+			title:       "Your repository has dependencies with security vulnerabilities",
+			alerts:      []map[string]any{{"number": 1, "state": "open"}},
+			expected:    cleaner.OutcomeKeep,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+
+			req := gock.New("https://api.github.com").
+				Get("/repos/owner/repo/dependabot/alerts").
+				MatchParam("state", "open")
+			if tc.pkg != "" {
+				req = req.MatchParam("package", tc.pkg)
+			}
+			req.Reply(200).JSON(tc.alerts)
+
+			n := &github.Notification{
+				ID:         github.Ptr("1"),
+				Reason:     github.Ptr(cleaner.ReasonSecurityAlert),
+				Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+				Subject: &github.NotificationSubject{
+					Title: github.Ptr(tc.title),
+					Type:  github.Ptr(tc.subjectType),
+				},
+			}
+
+			decision, err := cleaner.NewSecurityAlertRule().Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.True(t, gock.IsDone())
+		})
+	}
+
+	t.Run("keeps notifications when alerts are not accessible", func(t *testing.T) {
+		for _, status := range []int{403, 404} {
+			t.Run(fmt.Sprint(status), func(t *testing.T) {
+				defer gock.Off()
+
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/dependabot/alerts").
+					Reply(status).
+					JSON(map[string]any{"message": "Resource not accessible by integration"})
+
+				n := &github.Notification{
+					ID:         github.Ptr("1"),
+					Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+					Subject: &github.NotificationSubject{
+						Title: github.Ptr("Potential security vulnerability found in the lodash dependency"),
+						Type:  github.Ptr(cleaner.TypeRepositoryVulnerabilityAlert),
+					},
+				}
+
+				decision, err := cleaner.NewSecurityAlertRule().Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+				require.NoError(t, err)
+				assert.Equal(t, cleaner.OutcomeKeep, decision.Outcome)
+				assert.True(t, gock.IsDone())
+			})
+		}
+	})
+
+	t.Run("returns an error when rate limited", func(t *testing.T) {
+		defer gock.Off()
+
+		gock.New("https://api.github.com").
+			Get("/repos/owner/repo/dependabot/alerts").
+			Reply(403).
+			SetHeader("X-RateLimit-Limit", "5000").
+			SetHeader("X-RateLimit-Remaining", "0").
+			SetHeader("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix())).
+			JSON(map[string]any{"message": "API rate limit exceeded"})
+
+		n := &github.Notification{
+			ID:         github.Ptr("1"),
+			Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+			Subject: &github.NotificationSubject{
+				Title: github.Ptr("Potential security vulnerability found in the lodash dependency"),
+				Type:  github.Ptr(cleaner.TypeRepositoryVulnerabilityAlert),
+			},
+		}
+
+		_, err := cleaner.NewSecurityAlertRule().Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+		var rateLimitErr *github.RateLimitError
+		require.ErrorAs(t, err, &rateLimitErr)
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("skips other subject types", func(t *testing.T) {
+		n := &github.Notification{
+			ID:      github.Ptr("1"),
+			Subject: &github.NotificationSubject{Type: github.Ptr(cleaner.TypeIssue)},
+		}
+
+		decision, err := cleaner.NewSecurityAlertRule().Evaluate(context.Background(), cleaner.NewThread(nil, n))
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.OutcomeSkip, decision.Outcome)
+	})
+}
@@ -0,0 +1,100 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// SubjectStatusRule applies an outcome to notifications of issues and pull requests
+// that were locked, transferred to another repository, or converted to a discussion.
+// Each condition has its own outcome, and OutcomeSkip disables it.
+type SubjectStatusRule struct {
+	// Locked is the outcome for locked conversations.
+	Locked Outcome
+	// Transferred is the outcome for issues transferred to another repository.
+	Transferred Outcome
+	// Converted is the outcome for issues converted to a discussion.
+	Converted Outcome
+}
+
+// Name returns the rule name.
+func (r *SubjectStatusRule) Name() string {
+	return "subject_status"
+}
+
+// Evaluate checks the status of the issue or pull request related to the notification.
+func (r *SubjectStatusRule) Evaluate(ctx context.Context, t *Thread) (Decision, error) {
+	switch t.GetSubject().GetType() {
+	case TypePullRequest:
+		return r.evaluatePullRequest(ctx, t)
+	case TypeIssue:
+		return r.evaluateIssue(ctx, t)
+	default:
+		return Skip(), nil
+	}
+}
+
+// evaluatePullRequest checks whether the pull request related to the notification is locked.
+func (r *SubjectStatusRule) evaluatePullRequest(ctx context.Context, t *Thread) (Decision, error) {
+	pr, err := t.PullRequest(ctx)
+	if err != nil {
+		return Skip(), err
+	}
+	if pr.GetLocked() && r.Locked != OutcomeSkip {
+		return Decision{Outcome: r.Locked, Reason: fmt.Sprintf("pull request #%d is locked", pr.GetNumber())}, nil
+	}
+	return Skip(), nil
+}
+
+// evaluateIssue checks whether the issue related to the notification was converted, transferred or locked.
+func (r *SubjectStatusRule) evaluateIssue(ctx context.Context, t *Thread) (Decision, error) {
+	issue, err := t.Issue(ctx)
+	if errors.Is(err, ErrConvertedToDiscussion) {
+		if r.Converted == OutcomeSkip {
+			return Skip(), err
+		}
+		return Decision{Outcome: r.Converted, Reason: "issue was converted to a discussion"}, nil
+	}
+	if err != nil {
+		return Skip(), err
+	}
+
+	repo := issueRepository(issue.GetRepositoryURL())
+	if repo != "" && !strings.EqualFold(repo, threadRepository(t)) && r.Transferred != OutcomeSkip {
+		return Decision{Outcome: r.Transferred, Reason: fmt.Sprintf("issue was transferred to %s#%d", repo, issue.GetNumber())}, nil
+	}
+	if issue.GetLocked() && r.Locked != OutcomeSkip {
+		return Decision{Outcome: r.Locked, Reason: fmt.Sprintf("issue #%d is locked", issue.GetNumber())}, nil
+	}
+	return Skip(), nil
+}
+
+// issueRepository returns the full name (owner/repo) of the repository from its API URL.
+// Example URL: https://api.github.com/repos/owner/repo
+func issueRepository(repositoryURL string) string {
+	u, err := url.Parse(repositoryURL)
+	if err != nil {
+		return ""
+	}
+	fullName, ok := strings.CutPrefix(u.Path, "/repos/")
+	if !ok {
+		return ""
+	}
+	return fullName
+}
+
+// threadRepository returns the full name of the repository of the notification,
+// falling back to the repository of the subject URL.
+func threadRepository(t *Thread) string {
+	if fullName := t.GetRepository().GetFullName(); fullName != "" {
+		return fullName
+	}
+	ref, err := parseSubjectURL(t.GetSubject().GetURL())
+	if err != nil {
+		return ""
+	}
+	return ref.Owner + "/" + ref.Repo
+}
@@ -0,0 +1,113 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestSubjectStatusRule(t *testing.T) {
+	rule := &cleaner.SubjectStatusRule{
+		Locked:      cleaner.OutcomeKeep,
+		Transferred: cleaner.OutcomeDone,
+		Converted:   cleaner.OutcomeDone,
+	}
+
+	testCases := []struct {
+		name     string
+		rule     *cleaner.SubjectStatusRule
+		setup    func()
+		expected cleaner.Outcome
+	}{
+		{
+			name: "applies the locked action to locked issues",
+			rule: rule,
+			setup: func() {
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/issues/1").
+					Reply(200).
+					JSON(map[string]any{
+						"number":         1,
+						"locked":         true,
+						"repository_url": "https://api.github.com/repos/owner/repo",
+					})
+			},
+			expected: cleaner.OutcomeKeep,
+		},
+		{
+			name: "applies the transferred action to transferred issues",
+			rule: rule,
+			setup: func() {
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/issues/1").
+					Reply(301).
+					SetHeader("Location", "https://api.github.com/repos/other/repo/issues/5")
+				gock.New("https://api.github.com").
+					Get("/repos/other/repo/issues/5").
+					Reply(200).
+					JSON(map[string]any{
+						"number":         5,
+						"repository_url": "https://api.github.com/repos/other/repo",
+					})
+			},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name: "applies the converted action to issues converted to discussions",
+			rule: rule,
+			setup: func() {
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/issues/1").
+					Reply(301).
+					SetHeader("Location", "https://api.github.com/repos/owner/repo/discussions/1")
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/discussions/1").
+					Reply(404).
+					JSON(map[string]any{"message": "Not Found"})
+			},
+			expected: cleaner.OutcomeDone,
+		},
+		{
+			name: "skips conditions without action",
+			rule: &cleaner.SubjectStatusRule{Transferred: cleaner.OutcomeDone},
+			setup: func() {
+				gock.New("https://api.github.com").
+					Get("/repos/owner/repo/issues/1").
+					Reply(200).
+					JSON(map[string]any{
+						"number":         1,
+						"locked":         true,
+						"repository_url": "https://api.github.com/repos/owner/repo",
+					})
+			},
+			expected: cleaner.OutcomeSkip,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			defer gock.Off()
+			tc.setup()
+
+			n := &github.Notification{
+				ID:         github.Ptr("1"),
+				Repository: &github.Repository{FullName: github.Ptr("owner/repo")},
+				Subject: &github.NotificationSubject{
+					Type: github.Ptr(cleaner.TypeIssue),
+					URL:  github.Ptr("https://api.github.com/repos/owner/repo/issues/1"),
+				},
+			}
+
+			decision, err := tc.rule.Evaluate(context.Background(), cleaner.NewThread(setupMockClient(t), n))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, decision.Outcome)
+			assert.True(t, gock.IsDone())
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package cleaner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ErrConvertedToDiscussion is returned when the issue related to a notification was converted to a discussion.
+var ErrConvertedToDiscussion = errors.New("issue was converted to a discussion")
+
+// Thread is a notification being evaluated by the cleaner.
+// It lazily fetches the details of the notification subject and caches them,
+// so that several rules can inspect the same subject with a single API call.
+type Thread struct {
+	*github.Notification
+
+	client      *github.Client
+	viewer      *Viewer
+	pullRequest *github.PullRequest
+	issue       *github.Issue
+	release     *github.RepositoryRelease
+	discussion  *Discussion
+}
+
+// ThreadOption configures a Thread.
+type ThreadOption func(*Thread)
+
+// WithThreadViewer is an option to share the authenticated user between threads.
+func WithThreadViewer(viewer *Viewer) ThreadOption {
+	return func(t *Thread) {
+		t.viewer = viewer
+	}
+}
+
+// NewThread creates a new Thread for the given notification.
+// Unless a Viewer is given with WithThreadViewer, the thread uses its own Viewer.
+func NewThread(client *github.Client, n *github.Notification, opts ...ThreadOption) *Thread {
+	t := &Thread{
+		Notification: n,
+		client:       client,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.viewer == nil {
+		t.viewer = NewViewer(client)
+	}
+	return t
+}
+
+// Viewer returns the authenticated user.
+func (t *Thread) Viewer() *Viewer {
+	return t.viewer
+}
+
+// PullRequest returns the pull request related to the notification.
+func (t *Thread) PullRequest(ctx context.Context) (*github.PullRequest, error) {
+	if t.pullRequest != nil {
+		return t.pullRequest, nil
+	}
+
+	owner, repo, number, err := parseNotificationURL(t.GetSubject().GetURL())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing notification URL for notification %s: %w", t.GetID(), err)
+	}
+
+	pr, _, err := t.client.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching pull request %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	t.pullRequest = pr
+	return pr, nil
+}
+
+// Issue returns the issue related to the notification.
+func (t *Thread) Issue(ctx context.Context) (*github.Issue, error) {
+	if t.issue != nil {
+		return t.issue, nil
+	}
+
+	owner, repo, number, err := parseNotificationURL(t.GetSubject().GetURL())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing notification URL for notification %s: %w", t.GetID(), err)
+	}
+
+	// Transferred issues redirect to their new location, and issues converted
+	// to discussions redirect to the discussion, which is not a REST resource.
+	issue, resp, err := t.client.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		issue, err = t.resolveIssueRedirect(ctx, resp, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching issue %s/%s#%d: %w", owner, repo, number, err)
+	}
+
+	t.issue = issue
+	return issue, nil
+}
+
+// resolveIssueRedirect handles the error of an issue request: it returns the issue at the
+// redirect location, ErrConvertedToDiscussion when the issue was converted, or the error itself.
+func (t *Thread) resolveIssueRedirect(ctx context.Context, resp *github.Response, err error) (*github.Issue, error) {
+	var redirect *github.RedirectionError
+	switch {
+	case errors.As(err, &redirect) && redirect.Location != nil:
+		if isDiscussionPath(redirect.Location.Path) {
+			return nil, ErrConvertedToDiscussion
+		}
+		return t.fetchIssue(ctx, redirect.Location.String())
+	case resp != nil && resp.Request != nil && isDiscussionPath(resp.Request.URL.Path):
+		return nil, ErrConvertedToDiscussion
+	default:
+		return nil, err
+	}
+}
+
+// fetchIssue fetches the issue at the given API URL, used to follow redirects.
+func (t *Thread) fetchIssue(ctx context.Context, rawURL string) (*github.Issue, error) {
+	req, err := t.client.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := new(github.Issue)
+	if _, err := t.client.Do(ctx, req, issue); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// isDiscussionPath reports whether the URL path points to a discussion.
+func isDiscussionPath(p string) bool {
+	return strings.Contains(p, "/discussions/")
+}
+
+// Release returns the release related to the notification.
+func (t *Thread) Release(ctx context.Context) (*github.RepositoryRelease, error) {
+	if t.release != nil {
+		return t.release, nil
+	}
+
+	owner, repo, id, err := parseNotificationURL(t.GetSubject().GetURL())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing notification URL for notification %s: %w", t.GetID(), err)
+	}
+
+	release, _, err := t.client.Repositories.GetRelease(ctx, owner, repo, int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching release %s/%s/%d: %w", owner, repo, id, err)
+	}
+
+	t.release = release
+	return release, nil
+}
+
+// ClosedState returns how the issue or pull request related to the notification was closed.
+// It returns an empty state when the subject is still open or is not an issue or pull request.
+func (t *Thread) ClosedState(ctx context.Context) (ClosedState, error) {
+	switch t.GetSubject().GetType() {
+	case TypePullRequest:
+		pr, err := t.PullRequest(ctx)
+		if err != nil {
+			return "", err
+		}
+		return pullRequestClosedState(pr), nil
+	case TypeIssue:
+		issue, err := t.Issue(ctx)
+		if err != nil {
+			return "", err
+		}
+		return issueClosedState(issue), nil
+	default:
+		return "", nil
+	}
+}
+
+// Timestamp returns the given timestamp of the notification or its subject.
+// It returns a zero time when the timestamp is not set, for example the closing
+// date of an open issue, or the subject timestamps of a release notification.
+func (t *Thread) Timestamp(ctx context.Context, ts Timestamp) (time.Time, error) {
+	switch ts {
+	case TimestampUpdatedAt:
+		return t.GetUpdatedAt().Time, nil
+	case TimestampLastReadAt:
+		return t.GetLastReadAt().Time, nil
+	case TimestampSubjectUpdatedAt, TimestampSubjectClosedAt:
+	default:
+		return time.Time{}, fmt.Errorf("unknown timestamp %q", ts)
+	}
+
+	var updatedAt, closedAt time.Time
+	switch t.GetSubject().GetType() {
+	case TypePullRequest:
+		pr, err := t.PullRequest(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		updatedAt, closedAt = pr.GetUpdatedAt().Time, pr.GetClosedAt().Time
+	case TypeIssue:
+		issue, err := t.Issue(ctx)
+		if err != nil {
+			return time.Time{}, err
+		}
+		updatedAt, closedAt = issue.GetUpdatedAt().Time, issue.GetClosedAt().Time
+	}
+
+	if ts == TimestampSubjectClosedAt {
+		return closedAt, nil
+	}
+	return updatedAt, nil
+}
+
+// pullRequestClosedState returns how the pull request was closed, or an empty state if it is open.
+func pullRequestClosedState(pr *github.PullRequest) ClosedState {
+	switch {
+	case pr.GetState() != "closed":
+		return ""
+	case pr.GetMerged() || pr.MergedAt != nil:
+		return StateMerged
+	default:
+		return StateClosedUnmerged
+	}
+}
+
+// issueClosedState returns how the issue was closed, or an empty state if it is open.
+// Issues closed before GitHub introduced state reasons are considered completed.
+func issueClosedState(issue *github.Issue) ClosedState {
+	if issue.GetState() != "closed" {
+		return ""
+	}
+
+	switch reason := ClosedState(issue.GetStateReason()); reason {
+	case StateNotPlanned, StateDuplicate:
+		return reason
+	default:
+		return StateCompleted
+	}
+}
@@ -0,0 +1,42 @@
+package cleaner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/h2non/gock.v1"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+func TestNewThread(t *testing.T) {
+	t.Run("shares the given viewer", func(t *testing.T) {
+		defer gock.Off()
+
+		// A single mock: the authenticated user must be fetched only once.
+		gock.New("https://api.github.com").
+			Get("/user").
+			Reply(200).
+			JSON(map[string]any{"login": "me"})
+
+		client := setupMockClient(t)
+		viewer := cleaner.NewViewer(client)
+		for _, id := range []string{"1", "2"} {
+			thread := cleaner.NewThread(client, &github.Notification{ID: github.Ptr(id)}, cleaner.WithThreadViewer(viewer))
+			assert.Same(t, viewer, thread.Viewer())
+
+			login, err := thread.Viewer().Login(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, "me", login)
+		}
+		assert.True(t, gock.IsDone())
+	})
+
+	t.Run("creates a viewer by default", func(t *testing.T) {
+		thread := cleaner.NewThread(nil, &github.Notification{ID: github.Ptr("1")})
+		assert.NotNil(t, thread.Viewer())
+	})
+}
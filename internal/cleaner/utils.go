@@ -1,32 +1,80 @@
 package cleaner
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
-	"path"
 	"strconv"
 	"strings"
+
+	"github.com/google/go-github/v69/github"
 )
 
-// parseNotificationURL extracts owner, repo, and number from the GitHub API URL.
-// Example URL: https://api.github.com/repos/owner/repo/pulls/123
-func parseNotificationURL(rawURL string) (owner string, repo string, number int, err error) {
+// subjectRef identifies the subject of a notification from its GitHub API URL.
+type subjectRef struct {
+	Owner string
+	Repo  string
+	// Resource is the kind of subject, for example "pulls", "issues" or "commits".
+	Resource string
+	// ID is the subject identifier: a number for issues and pull requests, or a SHA for commits.
+	ID string
+}
+
+// parseSubjectURL extracts the subject reference from the GitHub API URL.
+// Example URL: https://api.github.com/repos/owner/repo/commits/abc123
+func parseSubjectURL(rawURL string) (subjectRef, error) {
 	u, err := url.Parse(rawURL)
 	if err != nil {
-		return "", "", 0, err
+		return subjectRef{}, err
 	}
 	segments := strings.Split(u.Path, "/")
-	// Expected path is: /repos/{owner}/{repo}/{resource}/{number}
-	if len(segments) < 5 {
-		return "", "", 0, fmt.Errorf("invalid URL format")
+	// Expected path is: /repos/{owner}/{repo}/{resource}/{id}
+	if len(segments) < 6 || segments[1] != "repos" {
+		return subjectRef{}, fmt.Errorf("invalid URL format")
 	}
-	owner = segments[2]
-	repo = segments[3]
-	// The resource segment (e.g., "pulls" or "issues") is segments[4]
-	numberStr := path.Base(u.Path)
-	number, err = strconv.Atoi(numberStr)
+	return subjectRef{
+		Owner:    segments[2],
+		Repo:     segments[3],
+		Resource: segments[4],
+		ID:       segments[len(segments)-1],
+	}, nil
+}
+
+// parseNotificationURL extracts owner, repo, and number from the GitHub API URL.
+// Example URL: https://api.github.com/repos/owner/repo/pulls/123
+func parseNotificationURL(rawURL string) (owner string, repo string, number int, err error) {
+	ref, err := parseSubjectURL(rawURL)
 	if err != nil {
 		return "", "", 0, err
 	}
-	return owner, repo, number, nil
+	number, err = strconv.Atoi(ref.ID)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("invalid %s number %q", ref.Resource, ref.ID)
+	}
+	return ref.Owner, ref.Repo, number, nil
+}
+
+// isNotFound reports whether the GitHub API response is a 404 Not Found.
+func isNotFound(resp *github.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusNotFound
+}
+
+// isForbidden reports whether the GitHub API response is a 403 Forbidden.
+func isForbidden(resp *github.Response) bool {
+	return resp != nil && resp.StatusCode == http.StatusForbidden
+}
+
+// isRateLimited reports whether the error is caused by the primary or secondary rate limit.
+// go-github reports both as 403 responses, which must not be confused with missing permissions.
+func isRateLimited(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr)
+}
+
+// splitRepository splits a repository full name into owner and repository name.
+func splitRepository(fullName string) (owner string, repo string) {
+	owner, repo, _ = strings.Cut(fullName, "/")
+	return owner, repo
 }
@@ -0,0 +1,71 @@
+package cleaner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// Viewer lazily fetches the authenticated user and caches it.
+// A single Viewer is shared by all the threads of a run, so that rules
+// checking who the user is cost a single API call.
+type Viewer struct {
+	client *github.Client
+	user   *github.User
+	// teams is the set of teams of the user, as lowercase "org/slug" keys.
+	teams map[string]bool
+}
+
+// NewViewer creates a new Viewer.
+func NewViewer(client *github.Client) *Viewer {
+	return &Viewer{client: client}
+}
+
+// Login returns the login of the authenticated user.
+func (v *Viewer) Login(ctx context.Context) (string, error) {
+	if v.user != nil {
+		return v.user.GetLogin(), nil
+	}
+
+	user, _, err := v.client.Users.Get(ctx, "")
+	if err != nil {
+		return "", fmt.Errorf("error fetching authenticated user: %w", err)
+	}
+
+	v.user = user
+	return user.GetLogin(), nil
+}
+
+// InTeam reports whether the authenticated user is a member of the team
+// with the given slug in the given organization.
+func (v *Viewer) InTeam(ctx context.Context, org, slug string) (bool, error) {
+	if v.teams == nil {
+		teams := make(map[string]bool)
+		opts := &github.ListOptions{PerPage: 100}
+		for {
+			page, resp, err := v.client.Teams.ListUserTeams(ctx, opts)
+			if err != nil {
+				return false, fmt.Errorf("error fetching teams of authenticated user: %w", err)
+			}
+
+			for _, team := range page {
+				teams[teamKey(team.GetOrganization().GetLogin(), team.GetSlug())] = true
+			}
+
+			if resp.NextPage == 0 {
+				break
+			}
+			opts.Page = resp.NextPage
+		}
+		v.teams = teams
+	}
+
+	return v.teams[teamKey(org, slug)], nil
+}
+
+// teamKey returns the key identifying a team in Viewer.teams.
+func teamKey(org, slug string) string {
+	return strings.ToLower(org + "/" + slug)
+}
@@ -0,0 +1,484 @@
+// Package config provides loading of the configuration file used by the clean command.
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+const appName = "github-notifications-cleaner"
+
+// Supported rule types.
+const (
+	RuleAge        = "age"
+	RuleClosed     = "closed"
+	RuleExpression = "expression"
+	RuleReason     = "reason"
+	RuleRelease    = "release"
+	RuleDiscussion = "discussion"
+	RuleCheckSuite = "check_suite"
+	RuleSecurity   = "security_alert"
+	RuleCommit     = "commit"
+	RuleDraft      = "draft"
+	RuleBot        = "bot"
+	RuleLabel      = "label"
+	RuleReview     = "review_request"
+	RuleSubject    = "subject_status"
+	RuleRead       = "read"
+	RuleRepo       = "repo"
+)
+
+// Config defines the structure of the configuration file.
+type Config struct {
+	// DaysThreshold is the default age threshold (in days) for the age rule.
+	DaysThreshold int `yaml:"days_threshold" toml:"days_threshold"`
+	// OlderThan is the default age threshold as a duration, such as "36h" or "2w".
+	// When set, it takes precedence over DaysThreshold.
+	OlderThan Duration `yaml:"older_than" toml:"older_than"`
+	// DryRun enables dry-run mode, where no notification is actually changed.
+	DryRun bool `yaml:"dry_run" toml:"dry_run"`
+	// BatchRead marks the threads with the read action as read with repository-wide calls at the end of the run.
+	BatchRead bool `yaml:"batch_read" toml:"batch_read"`
+	// IgnoreClosed also sets the subscription of the threads cleaned by the closed rules to ignored.
+	IgnoreClosed bool `yaml:"ignore_closed" toml:"ignore_closed"`
+	// Journal is the file where the thread subscription changes are recorded, so that they can be reverted.
+	// Defaults to DefaultJournalPath.
+	Journal string `yaml:"journal" toml:"journal"`
+	// Filters restricts which notifications are processed.
+	Filters FiltersConfig `yaml:"filters" toml:"filters"`
+	// Thresholds overrides the age threshold of the age rules for some repositories.
+	Thresholds []ThresholdConfig `yaml:"thresholds" toml:"thresholds"`
+	// Rules lists the rules to evaluate, in order. When empty, the default rules are used.
+	Rules []RuleConfig `yaml:"rules" toml:"rules"`
+	// NoisyRepos configures the detection of the repositories where many notifications are cleaned.
+	NoisyRepos NoisyReposConfig `yaml:"noisy_repos" toml:"noisy_repos"`
+}
+
+// NoisyReposConfig defines the detection of noisy repositories. See cleaner.NoisyRepos.
+type NoisyReposConfig struct {
+	// Threshold is the number of cleaned notifications from which a repository is noisy. Zero disables the detection.
+	Threshold int `yaml:"threshold" toml:"threshold"`
+	// Window is the period over which cleaned notifications are counted, such as "7d".
+	// When not set, only the current run is counted.
+	Window Duration `yaml:"window" toml:"window"`
+	// Mute sets the subscription of noisy repositories to ignored, instead of only recommending it.
+	Mute bool `yaml:"mute" toml:"mute"`
+	// History is the file where the counts of each run are kept. Defaults to DefaultHistoryPath.
+	History string `yaml:"history" toml:"history"`
+}
+
+// ThresholdConfig defines the age threshold for the repositories matching a glob pattern.
+type ThresholdConfig struct {
+	// Repo is a glob pattern matched against the repository full name (owner/repo).
+	Repo string `yaml:"repo" toml:"repo"`
+	Days int    `yaml:"days" toml:"days"`
+	// OlderThan is the threshold as a duration. It can be used instead of Days.
+	OlderThan Duration `yaml:"older_than" toml:"older_than"`
+}
+
+// maxAge returns the threshold as a duration.
+func (t ThresholdConfig) maxAge() time.Duration {
+	if t.OlderThan > 0 {
+		return time.Duration(t.OlderThan)
+	}
+	return time.Duration(t.Days) * 24 * time.Hour
+}
+
+// validate checks the threshold configuration for errors.
+func (t ThresholdConfig) validate() error {
+	if _, err := path.Match(t.Repo, ""); err != nil || t.Repo == "" {
+		return fmt.Errorf("invalid repository pattern %q", t.Repo)
+	}
+
+	if t.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+
+	if t.Days > 0 && t.OlderThan > 0 {
+		return fmt.Errorf("older_than cannot be combined with days")
+	}
+
+	if t.maxAge() == 0 {
+		return fmt.Errorf("days or older_than is required")
+	}
+
+	return nil
+}
+
+// Duration is a duration accepting the human syntax of cleaner.ParseDuration,
+// such as "36h", "2w" or "1d12h", in configuration files.
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := cleaner.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler, so that plain numbers are rejected
+// instead of being read as nanoseconds.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	return d.UnmarshalText([]byte(value.Value))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// defaultRules are the rules used when none are configured, matching cleaner.DefaultRules.
+var defaultRules = []RuleConfig{
+	{Type: RuleSecurity},
+	{Type: RuleAge},
+	{Type: RuleClosed},
+}
+
+// FiltersConfig defines the repository and organization filters.
+type FiltersConfig struct {
+	Repos        []string `yaml:"repos" toml:"repos"`
+	ExcludeRepos []string `yaml:"exclude_repos" toml:"exclude_repos"`
+	Orgs         []string `yaml:"orgs" toml:"orgs"`
+	ExcludeOrgs  []string `yaml:"exclude_orgs" toml:"exclude_orgs"`
+}
+
+// validate checks the filter patterns for errors.
+func (f FiltersConfig) validate() error {
+	for _, pattern := range slices.Concat(f.Repos, f.ExcludeRepos, f.Orgs, f.ExcludeOrgs) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid filter pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// RuleConfig defines a single rule and its parameters.
+type RuleConfig struct {
+	// Type is the rule type. See the Rule* constants for the supported values.
+	Type string `yaml:"type" toml:"type"`
+	// Enabled allows to turn a rule off without removing it. Defaults to true.
+	Enabled *bool `yaml:"enabled" toml:"enabled"`
+	// Priority orders the rules: rules with a higher priority are evaluated first,
+	// and rules with the same priority keep their declaration order. Defaults to 0.
+	Priority int `yaml:"priority" toml:"priority"`
+	// Days is the age threshold for the age rule, where it defaults to DaysThreshold.
+	// The reason, repo and commit rules only apply to notifications (or commits) older than Days, when set.
+	// The read rule applies to notifications read (or, with Unread, updated) more than Days ago.
+	Days int `yaml:"days" toml:"days"`
+	// Action is the outcome of the reason, label and repo rules when they match: "done" (default) or "keep".
+	Action string `yaml:"action" toml:"action"`
+	// Actions are applied to the notifications cleaned by the rule: "read", "done", "unsubscribe" or "ignore".
+	// Several actions can be combined. Defaults to "done".
+	Actions []string `yaml:"actions" toml:"actions"`
+	// States restricts the closed rule to subjects closed in the given states.
+	States []string `yaml:"states" toml:"states"`
+	// Expression is the condition of the expression rule.
+	Expression string `yaml:"expression" toml:"expression"`
+	// Reasons lists the notification reasons matched by the reason rule.
+	Reasons []string `yaml:"reasons" toml:"reasons"`
+	// Repos lists the repository patterns (owner/repo) matched by the repo rule.
+	Repos []string `yaml:"repos" toml:"repos"`
+	// Labels lists the labels matched by the label rule.
+	Labels []string `yaml:"labels" toml:"labels"`
+	// Logins lists additional accounts considered as bots by the bot rule.
+	Logins []string `yaml:"logins" toml:"logins"`
+	// OlderThan is the threshold of the rule as a duration, such as "36h" or "2w".
+	// It can be used instead of Days or Hours by every rule accepting them, and is rejected by the other rules.
+	OlderThan Duration `yaml:"older_than" toml:"older_than"`
+	// Timestamp is the timestamp the age rule applies to. See cleaner.Timestamps for the supported values.
+	Timestamp string `yaml:"timestamp" toml:"timestamp"`
+	// Hours is the age threshold of the bot rule, and one of the conditions of the release rule.
+	// Prereleases, Drafts and OnlyMajorMinor are the other conditions of the release rule.
+	Prereleases    bool `yaml:"prereleases" toml:"prereleases"`
+	Drafts         bool `yaml:"drafts" toml:"drafts"`
+	Hours          int  `yaml:"hours" toml:"hours"`
+	OnlyMajorMinor bool `yaml:"only_major_minor" toml:"only_major_minor"`
+	// Closed, Locked and Answered are the conditions of the discussion rule.
+	Closed   bool `yaml:"closed" toml:"closed"`
+	Locked   bool `yaml:"locked" toml:"locked"`
+	Answered bool `yaml:"answered" toml:"answered"`
+	// Succeeded and BranchGone are the conditions of the check_suite rule.
+	Succeeded  bool `yaml:"succeeded" toml:"succeeded"`
+	BranchGone bool `yaml:"branch_gone" toml:"branch_gone"`
+	// Unread makes the read rule match unread notifications instead of read ones.
+	Unread bool `yaml:"unread" toml:"unread"`
+	// DefaultBranch restricts the commit rule to commits on the repository default branch.
+	DefaultBranch bool `yaml:"default_branch" toml:"default_branch"`
+	// OnLocked, OnTransferred and OnConverted are the actions ("done" or "keep") of the subject_status rule
+	// for locked subjects, transferred issues and issues converted to discussions. Empty values disable a condition.
+	OnLocked      string `yaml:"on_locked" toml:"on_locked"`
+	OnTransferred string `yaml:"on_transferred" toml:"on_transferred"`
+	OnConverted   string `yaml:"on_converted" toml:"on_converted"`
+}
+
+// outcome returns the outcome of the rule, defaulting to done.
+func (r RuleConfig) outcome() (cleaner.Outcome, error) {
+	if r.Action == "" {
+		return cleaner.OutcomeDone, nil
+	}
+	return cleaner.ParseOutcome(r.Action)
+}
+
+// maxAge returns the threshold of the rule: OlderThan when set, or n times unit,
+// where n is the value of Days or Hours.
+func (r RuleConfig) maxAge(n int, unit time.Duration) time.Duration {
+	if r.OlderThan > 0 {
+		return time.Duration(r.OlderThan)
+	}
+	return time.Duration(n) * unit
+}
+
+// parseAction parses an optional action, where an empty action disables the condition.
+func parseAction(action string) (cleaner.Outcome, error) {
+	if action == "" {
+		return cleaner.OutcomeSkip, nil
+	}
+	return cleaner.ParseOutcome(action)
+}
+
+// IsEnabled reports whether the rule is enabled.
+func (r RuleConfig) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// Default returns the default configuration.
+func Default() *Config {
+	return &Config{
+		DaysThreshold: cleaner.DefaultDaysThreshold,
+		Journal:       DefaultJournalPath(),
+		NoisyRepos: NoisyReposConfig{
+			History: DefaultHistoryPath(),
+		},
+	}
+}
+
+// DefaultPath returns the default location of the configuration file, following the XDG base directory specification.
+// It returns the first existing file among config.yaml, config.yml and config.toml, or the config.yaml path if none exists.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+
+	candidates := []string{"config.yaml", "config.yml", "config.toml"}
+	for _, name := range candidates {
+		p := filepath.Join(dir, appName, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+
+	return filepath.Join(dir, appName, candidates[0])
+}
+
+// DefaultJournalPath returns the default location of the journal file, following the XDG base directory specification.
+func DefaultJournalPath() string {
+	return statePath("journal.jsonl")
+}
+
+// DefaultHistoryPath returns the default location of the noisy repositories history file,
+// following the XDG base directory specification.
+func DefaultHistoryPath() string {
+	return statePath("history.json")
+}
+
+// statePath returns the path of the given file in the state directory of the application.
+func statePath(name string) string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, appName, name)
+}
+
+// Load reads the configuration file at the given path.
+// The format is detected from the file extension and can be YAML or TOML.
+// Values not present in the file keep their defaults.
+func Load(p string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Clean(p))
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	cfg := Default()
+	switch ext := strings.ToLower(filepath.Ext(p)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("error parsing config file %s: %w", p, err)
+		}
+	case ".toml":
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %w", p, err)
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			return nil, fmt.Errorf("error parsing config file %s: unknown field %q", p, undecoded[0].String())
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q", ext)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", p, err)
+	}
+
+	return cfg, nil
+}
+
+// LoadOrDefault reads the configuration file at the given path, like Load.
+// The default configuration is returned when the path is empty, or when the file
+// does not exist and is not required.
+func LoadOrDefault(p string, required bool) (*Config, error) {
+	if p == "" {
+		return Default(), nil
+	}
+
+	cfg, err := Load(p)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		slog.Debug("config file not found, using defaults", slog.String("path", p))
+		return Default(), nil
+	}
+	return cfg, err
+}
+
+// Validate checks the configuration for errors.
+func (c *Config) Validate() error {
+	if c.DaysThreshold < 0 {
+		return fmt.Errorf("days_threshold must not be negative")
+	}
+
+	if err := c.Filters.validate(); err != nil {
+		return err
+	}
+
+	for i, t := range c.Thresholds {
+		if err := t.validate(); err != nil {
+			return fmt.Errorf("thresholds[%d]: %w", i, err)
+		}
+	}
+
+	if c.NoisyRepos.Threshold < 0 {
+		return fmt.Errorf("noisy_repos: threshold must not be negative")
+	}
+
+	for i, r := range c.Rules {
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Filter returns the cleaner filter defined by the configuration.
+func (c *Config) Filter() cleaner.Filter {
+	return cleaner.Filter{
+		Repos:        c.Filters.Repos,
+		ExcludeRepos: c.Filters.ExcludeRepos,
+		Orgs:         c.Filters.Orgs,
+		ExcludeOrgs:  c.Filters.ExcludeOrgs,
+	}
+}
+
+// NoisyReposOptions returns the cleaner options for noisy repositories defined by the configuration.
+// The history file is only used when a window is set.
+func (c *Config) NoisyReposOptions() cleaner.NoisyRepos {
+	opts := cleaner.NoisyRepos{
+		Threshold: c.NoisyRepos.Threshold,
+		Mute:      c.NoisyRepos.Mute,
+		Window:    time.Duration(c.NoisyRepos.Window),
+	}
+	if opts.Window > 0 {
+		opts.HistoryPath = c.NoisyRepos.History
+	}
+	return opts
+}
+
+// Threshold returns the default age threshold.
+func (c *Config) Threshold() time.Duration {
+	if c.OlderThan > 0 {
+		return time.Duration(c.OlderThan)
+	}
+	return time.Duration(c.DaysThreshold) * 24 * time.Hour
+}
+
+// BuildRules returns the enabled rules defined by the configuration, in evaluation order:
+// by descending priority, then in declaration order.
+// When no rules are configured, the default age and closed rules are returned.
+func (c *Config) BuildRules() ([]cleaner.Rule, error) {
+	ruleConfigs := c.Rules
+	if len(ruleConfigs) == 0 {
+		ruleConfigs = defaultRules
+	}
+
+	// Indexes are sorted rather than the rules, so that errors refer to the declaration order.
+	order := make([]int, len(ruleConfigs))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(ruleConfigs[b].Priority, ruleConfigs[a].Priority)
+	})
+
+	rules := make([]cleaner.Rule, 0, len(ruleConfigs))
+	for _, i := range order {
+		r := ruleConfigs[i]
+		if !r.IsEnabled() {
+			continue
+		}
+
+		rule, err := c.buildRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d]: %w", i, err)
+		}
+
+		if len(r.Actions) > 0 {
+			actions, err := cleaner.ParseActions(r.Actions)
+			if err != nil {
+				return nil, fmt.Errorf("rules[%d]: %w", i, err)
+			}
+			rule = cleaner.WithActions(rule, actions...)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// buildRule creates the rule defined by the given rule configuration.
+func (c *Config) buildRule(r RuleConfig) (cleaner.Rule, error) {
+	rt, ok := ruleTypes[r.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown rule type %q", r.Type)
+	}
+	return rt.build(c, r)
+}
@@ -0,0 +1,490 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+	"github.com/brpaz/github-notifications-cleaner/internal/config"
+)
+
+func writeConfig(t *testing.T, name string, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	return p
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("loads YAML config", func(t *testing.T) {
+		p := writeConfig(t, "config.yaml", `
+days_threshold: 10
+dry_run: true
+filters:
+  repos: ["my-org/*"]
+  exclude_repos: ["my-org/noisy"]
+  orgs: ["kubernetes"]
+  exclude_orgs: ["spam-org"]
+rules:
+  - type: age
+    days: 5
+  - type: closed
+    enabled: false
+`)
+
+		cfg, err := config.Load(p)
+		require.NoError(t, err)
+		assert.Equal(t, 10, cfg.DaysThreshold)
+		assert.True(t, cfg.DryRun)
+		assert.Equal(t, []string{"my-org/*"}, cfg.Filters.Repos)
+		assert.Equal(t, []string{"my-org/noisy"}, cfg.Filters.ExcludeRepos)
+		assert.Equal(t, []string{"kubernetes"}, cfg.Filters.Orgs)
+		assert.Equal(t, []string{"spam-org"}, cfg.Filters.ExcludeOrgs)
+		require.Len(t, cfg.Rules, 2)
+		assert.Equal(t, config.RuleAge, cfg.Rules[0].Type)
+		assert.Equal(t, 5, cfg.Rules[0].Days)
+		assert.False(t, cfg.Rules[1].IsEnabled())
+	})
+
+	t.Run("loads TOML config", func(t *testing.T) {
+		p := writeConfig(t, "config.toml", `
+days_threshold = 10
+
+[filters]
+repos = ["my-org/*"]
+
+[[rules]]
+type = "closed"
+`)
+
+		cfg, err := config.Load(p)
+		require.NoError(t, err)
+		assert.Equal(t, 10, cfg.DaysThreshold)
+		assert.Equal(t, []string{"my-org/*"}, cfg.Filters.Repos)
+		require.Len(t, cfg.Rules, 1)
+		assert.Equal(t, config.RuleClosed, cfg.Rules[0].Type)
+	})
+
+	t.Run("loads durations", func(t *testing.T) {
+		p := writeConfig(t, "config.yaml", `
+older_than: 1d12h
+thresholds:
+  - repo: "my-org/*"
+    older_than: 2w
+rules:
+  - type: reason
+    reasons: [ci_activity]
+    older_than: 6h
+`)
+
+		cfg, err := config.Load(p)
+		require.NoError(t, err)
+		assert.Equal(t, 36*time.Hour, cfg.Threshold())
+		assert.Equal(t, config.Duration(14*24*time.Hour), cfg.Thresholds[0].OlderThan)
+		assert.Equal(t, config.Duration(6*time.Hour), cfg.Rules[0].OlderThan)
+	})
+
+	t.Run("loads durations from TOML", func(t *testing.T) {
+		p := writeConfig(t, "config.toml", `older_than = "36h"`)
+
+		cfg, err := config.Load(p)
+		require.NoError(t, err)
+		assert.Equal(t, 36*time.Hour, cfg.Threshold())
+	})
+
+	t.Run("loads deployment approval reasons", func(t *testing.T) {
+		p := writeConfig(t, "config.yaml", `
+rules:
+  - type: reason
+    reasons: [approval_requested]
+    action: keep
+`)
+
+		cfg, err := config.Load(p)
+		require.NoError(t, err)
+		assert.Equal(t, []string{cleaner.ReasonApprovalRequested}, cfg.Rules[0].Reasons)
+	})
+
+	t.Run("keeps defaults for missing values", func(t *testing.T) {
+		p := writeConfig(t, "config.yaml", "")
+
+		cfg, err := config.Load(p)
+		require.NoError(t, err)
+		assert.Equal(t, config.Default(), cfg)
+	})
+
+	t.Run("returns error", func(t *testing.T) {
+		testCases := []struct {
+			name    string
+			file    string
+			content string
+		}{
+			{"unknown YAML field", "config.yaml", "unknown: true"},
+			{"unknown TOML field", "config.toml", "unknown = true"},
+			{"unsupported format", "config.json", "{}"},
+			{"unknown rule type", "config.yaml", "rules: [{type: unknown}]"},
+			{"negative days", "config.yaml", "rules: [{type: age, days: -1}]"},
+			{"unknown closed state", "config.yaml", "rules: [{type: closed, states: [unknown]}]"},
+			{"missing reasons", "config.yaml", "rules: [{type: reason}]"},
+			{"unknown reason", "config.yaml", "rules: [{type: reason, reasons: [unknown]}]"},
+			{"unknown action", "config.yaml", "rules: [{type: reason, reasons: [mention], action: unknown}]"},
+			{"unsupported action", "config.yaml", "rules: [{type: age, action: keep}]"},
+			{"release rule without conditions", "config.yaml", "rules: [{type: release}]"},
+			{"negative hours", "config.yaml", "rules: [{type: release, hours: -1}]"},
+			{"days on bot rules", "config.yaml", "rules: [{type: bot, days: 2}]"},
+			{"hours on age rules", "config.yaml", "rules: [{type: age, hours: 2}]"},
+			{"days on rules without threshold", "config.yaml", "rules: [{type: draft, days: 2}]"},
+			{"discussion rule without conditions", "config.yaml", "rules: [{type: discussion}]"},
+			{"check_suite rule without conditions", "config.yaml", "rules: [{type: check_suite}]"},
+			{"commit rule without conditions", "config.yaml", "rules: [{type: commit}]"},
+			{"missing labels", "config.yaml", "rules: [{type: label}]"},
+			{"missing repos", "config.yaml", "rules: [{type: repo}]"},
+			{"negative noisy repositories threshold", "config.yaml", "noisy_repos: {threshold: -1}"},
+			{"unknown action", "config.yaml", "rules: [{type: age, actions: [archive]}]"},
+			{"actions on keep rules", "config.yaml", "rules: [{type: reason, reasons: [mention], action: keep, actions: [read]}]"},
+			{"invalid repo rule pattern", "config.yaml", "rules: [{type: repo, repos: ['[']}]"},
+			{"read rule without days", "config.yaml", "rules: [{type: read}]"},
+			{"unread rule without days", "config.yaml", "rules: [{type: read, unread: true}]"},
+			{"invalid duration", "config.yaml", "older_than: 3600"},
+			{"older_than combined with days", "config.yaml", "rules: [{type: age, days: 1, older_than: 12h}]"},
+			{"older_than on rules without threshold", "config.yaml", "rules: [{type: closed, older_than: 12h}]"},
+			{"unknown timestamp", "config.yaml", "rules: [{type: age, timestamp: created_at}]"},
+			{"timestamp on other rules", "config.yaml", "rules: [{type: closed, timestamp: updated_at}]"},
+			{"subject_status rule without conditions", "config.yaml", "rules: [{type: subject_status}]"},
+			{"invalid subject_status action", "config.yaml", "rules: [{type: subject_status, on_locked: archive}]"},
+			{"missing expression", "config.yaml", "rules: [{type: expression}]"},
+			{"invalid repository pattern", "config.yaml", "filters: {repos: ['[']}"},
+			{"invalid threshold pattern", "config.yaml", "thresholds: [{repo: '[', days: 1}]"},
+			{"missing threshold pattern", "config.yaml", "thresholds: [{days: 1}]"},
+			{"negative threshold days", "config.yaml", "thresholds: [{repo: 'owner/*', days: -1}]"},
+			{"threshold without days", "config.yaml", "thresholds: [{repo: 'owner/*'}]"},
+			{"threshold with days and older_than", "config.yaml", "thresholds: [{repo: 'owner/*', days: 1, older_than: 12h}]"},
+			{"invalid organization pattern", "config.yaml", "filters: {exclude_orgs: ['[']}"},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				p := writeConfig(t, tc.file, tc.content)
+				_, err := config.Load(p)
+				assert.Error(t, err)
+			})
+		}
+	})
+
+	t.Run("returns error when file does not exist", func(t *testing.T) {
+		_, err := config.Load(filepath.Join(t.TempDir(), "config.yaml"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestLoadOrDefault(t *testing.T) {
+	t.Run("loads existing file", func(t *testing.T) {
+		p := writeConfig(t, "config.yaml", "journal: /tmp/journal.jsonl")
+
+		cfg, err := config.LoadOrDefault(p, false)
+		require.NoError(t, err)
+		assert.Equal(t, "/tmp/journal.jsonl", cfg.Journal)
+	})
+
+	t.Run("returns defaults when file does not exist", func(t *testing.T) {
+		cfg, err := config.LoadOrDefault(filepath.Join(t.TempDir(), "config.yaml"), false)
+		require.NoError(t, err)
+		assert.Equal(t, config.Default(), cfg)
+	})
+
+	t.Run("returns error when required file does not exist", func(t *testing.T) {
+		_, err := config.LoadOrDefault(filepath.Join(t.TempDir(), "config.yaml"), true)
+		require.Error(t, err)
+	})
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Run("uses XDG_CONFIG_HOME", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", dir)
+		assert.Equal(t, filepath.Join(dir, "github-notifications-cleaner", "config.yaml"), config.DefaultPath())
+	})
+
+	t.Run("finds existing TOML file", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("XDG_CONFIG_HOME", dir)
+
+		p := filepath.Join(dir, "github-notifications-cleaner", "config.toml")
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o700))
+		require.NoError(t, os.WriteFile(p, nil, 0o600))
+
+		assert.Equal(t, p, config.DefaultPath())
+	})
+}
+
+func TestDefaultJournalPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+	assert.Equal(t, filepath.Join(dir, "github-notifications-cleaner", "journal.jsonl"), config.DefaultJournalPath())
+}
+
+func TestNoisyReposOptions(t *testing.T) {
+	cfg := config.Default()
+	cfg.NoisyRepos = config.NoisyReposConfig{Threshold: 20, Mute: true, History: "history.json"}
+	assert.Equal(t, cleaner.NoisyRepos{Threshold: 20, Mute: true}, cfg.NoisyReposOptions(), "expected the history to be unused without window")
+
+	cfg.NoisyRepos.Window = config.Duration(7 * 24 * time.Hour)
+	assert.Equal(t, cleaner.NoisyRepos{
+		Threshold:   20,
+		Mute:        true,
+		HistoryPath: "history.json",
+		Window:      7 * 24 * time.Hour,
+	}, cfg.NoisyReposOptions())
+}
+
+func TestBuildRules(t *testing.T) {
+	t.Run("returns default rules without configured rules", func(t *testing.T) {
+		rules, err := config.Default().BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, cleaner.DefaultRules(cleaner.DefaultOlderThan), rules)
+	})
+
+	t.Run("applies thresholds to age rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Thresholds = []config.ThresholdConfig{
+			{Repo: "renovate-bot/*", Days: 3},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Contains(t, rules, cleaner.NewAgeRule(30*24*time.Hour, cleaner.AgeOverride{
+			Pattern: "renovate-bot/*",
+			MaxAge:  3 * 24 * time.Hour,
+		}))
+	})
+
+	t.Run("builds enabled rules in order", func(t *testing.T) {
+		disabled := false
+		cfg := config.Default()
+		cfg.DaysThreshold = 7
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleClosed, States: []string{"merged"}},
+			{Type: config.RuleAge},
+			{Type: config.RuleAge, Days: 3, Enabled: &disabled},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		require.Len(t, rules, 2)
+		assert.Equal(t, cleaner.NewClosedSubjectRule(cleaner.StateMerged), rules[0])
+		require.IsType(t, &cleaner.AgeRule{}, rules[1])
+		assert.Equal(t, cleaner.NewAgeRule(7*24*time.Hour), rules[1])
+	})
+
+	t.Run("builds rules with durations", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.OlderThan = config.Duration(36 * time.Hour)
+		cfg.Thresholds = []config.ThresholdConfig{
+			{Repo: "my-org/*", OlderThan: config.Duration(12 * time.Hour)},
+		}
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleAge},
+			{Type: config.RuleAge, OlderThan: config.Duration(2 * time.Hour), Timestamp: "subject.closed_at"},
+			{Type: config.RuleBot, OlderThan: config.Duration(90 * time.Minute)},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		override := cleaner.AgeOverride{Pattern: "my-org/*", MaxAge: 12 * time.Hour}
+		closedAge := cleaner.NewAgeRule(2*time.Hour, override)
+		closedAge.Timestamp = cleaner.TimestampSubjectClosedAt
+		assert.Equal(t, []cleaner.Rule{
+			cleaner.NewAgeRule(36*time.Hour, override),
+			closedAge,
+			&cleaner.BotRule{MaxAge: 90 * time.Minute},
+		}, rules)
+	})
+
+	t.Run("orders rules by priority", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleAge},
+			{Type: config.RuleClosed},
+			{Type: config.RuleRepo, Repos: []string{"my-org/on-call-*"}, Action: "keep", Priority: 10},
+			{Type: config.RuleReason, Reasons: []string{"assign", "mention"}, Action: "keep", Priority: 10},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			cleaner.NewRepoRule([]string{"my-org/on-call-*"}, 0, cleaner.OutcomeKeep),
+			cleaner.NewReasonRule([]string{"assign", "mention"}, 0, cleaner.OutcomeKeep),
+			cleaner.NewAgeRule(cleaner.DefaultOlderThan),
+			cleaner.NewClosedSubjectRule(),
+		}, rules)
+	})
+
+	t.Run("ignores closed threads when enabled", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.IgnoreClosed = true
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Contains(t, rules, &cleaner.ClosedSubjectRule{Ignore: true})
+	})
+
+	t.Run("wraps rules with actions", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleClosed, Actions: []string{"done", "ignore"}},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			cleaner.WithActions(cleaner.NewClosedSubjectRule(), cleaner.ActionDone, cleaner.ActionIgnore),
+		}, rules)
+	})
+
+	t.Run("builds reason rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleReason, Reasons: []string{"mention"}, Action: "keep"},
+			{Type: config.RuleReason, Reasons: []string{"ci_activity"}, Days: 1},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			cleaner.NewReasonRule([]string{"mention"}, 0, cleaner.OutcomeKeep),
+			cleaner.NewReasonRule([]string{"ci_activity"}, 24*time.Hour, cleaner.OutcomeDone),
+		}, rules)
+	})
+
+	t.Run("builds release rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleRelease, Prereleases: true, Hours: 36, OnlyMajorMinor: true},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			&cleaner.ReleaseRule{Prereleases: true, MaxAge: 36 * time.Hour, KeepOnlyMajorMinor: true},
+		}, rules)
+	})
+
+	t.Run("builds discussion rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleDiscussion, Closed: true, Answered: true},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			&cleaner.DiscussionRule{Closed: true, Answered: true},
+		}, rules)
+	})
+
+	t.Run("builds check_suite rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleCheckSuite, Succeeded: true, BranchGone: true},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			&cleaner.CheckSuiteRule{Succeeded: true, BranchGone: true},
+		}, rules)
+	})
+
+	t.Run("builds commit rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleCommit, Days: 7, DefaultBranch: true},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			&cleaner.CommitRule{MaxAge: 7 * 24 * time.Hour, OnDefaultBranch: true},
+		}, rules)
+	})
+
+	t.Run("builds bot rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleBot, Logins: []string{"renovate-self-hosted"}, Hours: 12},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			&cleaner.BotRule{Logins: []string{"renovate-self-hosted"}, MaxAge: 12 * time.Hour},
+		}, rules)
+	})
+
+	t.Run("builds review request rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{{Type: config.RuleReview}}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{cleaner.NewReviewRequestRule()}, rules)
+	})
+
+	t.Run("builds read rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleRead, Days: 7},
+			{Type: config.RuleRead, Unread: true, Days: 30},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			&cleaner.ReadRule{MaxAge: 7 * 24 * time.Hour},
+			&cleaner.ReadRule{Unread: true, MaxAge: 30 * 24 * time.Hour},
+		}, rules)
+	})
+
+	t.Run("builds subject status rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleSubject, OnLocked: "keep", OnConverted: "done"},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			&cleaner.SubjectStatusRule{Locked: cleaner.OutcomeKeep, Converted: cleaner.OutcomeDone},
+		}, rules)
+	})
+
+	t.Run("builds label rules", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleLabel, Labels: []string{"security", "P0"}, Action: "keep"},
+			{Type: config.RuleLabel, Labels: []string{"wontfix"}},
+		}
+
+		rules, err := cfg.BuildRules()
+		require.NoError(t, err)
+		assert.Equal(t, []cleaner.Rule{
+			cleaner.NewLabelRule([]string{"security", "P0"}, cleaner.OutcomeKeep),
+			cleaner.NewLabelRule([]string{"wontfix"}, cleaner.OutcomeDone),
+		}, rules)
+	})
+
+	t.Run("returns error for invalid expressions", func(t *testing.T) {
+		cfg := config.Default()
+		cfg.Rules = []config.RuleConfig{
+			{Type: config.RuleExpression, Expression: "unknown =="},
+		}
+
+		_, err := cfg.BuildRules()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/brpaz/github-notifications-cleaner/internal/cleaner"
+)
+
+// Threshold fields of the rule types.
+const (
+	thresholdDays  = "days"
+	thresholdHours = "hours"
+)
+
+// ruleType describes how a rule type is validated and built from its configuration.
+type ruleType struct {
+	// threshold is the field holding the threshold of the rule type: thresholdDays, thresholdHours,
+	// or empty when the rule type has no threshold.
+	threshold string
+	// validate checks the parameters specific to the rule type. It can be nil.
+	validate func(r RuleConfig) error
+	// build creates the rule.
+	build func(c *Config, r RuleConfig) (cleaner.Rule, error)
+}
+
+// ruleTypes are the supported rule types.
+var ruleTypes = map[string]ruleType{
+	RuleAge:        {threshold: thresholdDays, validate: validateAgeRule, build: buildAgeRule},
+	RuleClosed:     {validate: validateClosedRule, build: buildClosedRule},
+	RuleExpression: {validate: validateExpressionRule, build: buildExpressionRule},
+	RuleReason:     {threshold: thresholdDays, validate: validateReasonRule, build: buildReasonRule},
+	RuleRelease:    {threshold: thresholdHours, validate: validateReleaseRule, build: buildReleaseRule},
+	RuleDiscussion: {validate: validateDiscussionRule, build: buildDiscussionRule},
+	RuleCheckSuite: {validate: validateCheckSuiteRule, build: buildCheckSuiteRule},
+	RuleSecurity:   {build: buildSecurityRule},
+	RuleCommit:     {threshold: thresholdDays, validate: validateCommitRule, build: buildCommitRule},
+	RuleDraft:      {build: buildDraftRule},
+	RuleBot:        {threshold: thresholdHours, build: buildBotRule},
+	RuleLabel:      {validate: validateLabelRule, build: buildLabelRule},
+	RuleReview:     {build: buildReviewRule},
+	RuleSubject:    {validate: validateSubjectRule, build: buildSubjectRule},
+	RuleRead:       {threshold: thresholdDays, validate: validateReadRule, build: buildReadRule},
+	RuleRepo:       {threshold: thresholdDays, validate: validateRepoRule, build: buildRepoRule},
+}
+
+// validate checks the rule configuration for errors.
+func (r RuleConfig) validate() error {
+	rt, ok := ruleTypes[r.Type]
+	if !ok {
+		return fmt.Errorf("unknown rule type %q", r.Type)
+	}
+
+	if rt.validate != nil {
+		if err := rt.validate(r); err != nil {
+			return err
+		}
+	}
+
+	if err := r.validateThresholdFields(rt); err != nil {
+		return err
+	}
+
+	if err := r.validateThreshold(); err != nil {
+		return err
+	}
+
+	if r.Timestamp != "" && r.Type != RuleAge {
+		return fmt.Errorf("timestamp is not supported by %s rules", r.Type)
+	}
+
+	return r.validateActions()
+}
+
+// validateThresholdFields checks that the threshold fields set are supported by the rule type,
+// as the other ones would be silently ignored.
+func (r RuleConfig) validateThresholdFields(rt ruleType) error {
+	if r.Days != 0 && rt.threshold != thresholdDays {
+		return unsupportedThresholdError(thresholdDays, r.Type, rt)
+	}
+
+	if r.Hours != 0 && rt.threshold != thresholdHours {
+		return unsupportedThresholdError(thresholdHours, r.Type, rt)
+	}
+
+	if r.OlderThan != 0 && rt.threshold == "" {
+		return fmt.Errorf("older_than is not supported by %s rules", r.Type)
+	}
+
+	return nil
+}
+
+// unsupportedThresholdError returns the error for a threshold field not supported by the rule type.
+func unsupportedThresholdError(field string, ruleType string, rt ruleType) error {
+	if rt.threshold == "" {
+		return fmt.Errorf("%s is not supported by %s rules", field, ruleType)
+	}
+	return fmt.Errorf("%s is not supported by %s rules, use %s", field, ruleType, rt.threshold)
+}
+
+// validateThreshold checks the threshold parameters common to the rule types.
+func (r RuleConfig) validateThreshold() error {
+	if r.Hours < 0 {
+		return fmt.Errorf("hours must not be negative")
+	}
+
+	if r.Days < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+
+	if r.OlderThan > 0 && (r.Days != 0 || r.Hours != 0) {
+		return fmt.Errorf("older_than cannot be combined with days or hours")
+	}
+
+	return nil
+}
+
+// validateActions checks the action and actions parameters.
+func (r RuleConfig) validateActions() error {
+	if r.Action != "" && !slices.Contains([]string{RuleReason, RuleLabel, RuleRepo}, r.Type) {
+		return fmt.Errorf("action is not supported by %s rules", r.Type)
+	}
+
+	if _, err := r.outcome(); err != nil {
+		return err
+	}
+
+	if _, err := cleaner.ParseActions(r.Actions); err != nil {
+		return err
+	}
+
+	if len(r.Actions) > 0 && r.Action == "keep" {
+		return fmt.Errorf("actions cannot be combined with the keep action")
+	}
+
+	return nil
+}
+
+func validateAgeRule(r RuleConfig) error {
+	if r.Timestamp != "" && !slices.Contains(cleaner.Timestamps, cleaner.Timestamp(r.Timestamp)) {
+		return fmt.Errorf("unknown timestamp %q", r.Timestamp)
+	}
+	return nil
+}
+
+func validateClosedRule(r RuleConfig) error {
+	for _, state := range r.States {
+		if !slices.Contains(cleaner.ClosedStates, cleaner.ClosedState(state)) {
+			return fmt.Errorf("unknown closed state %q", state)
+		}
+	}
+	return nil
+}
+
+func validateExpressionRule(r RuleConfig) error {
+	if r.Expression == "" {
+		return fmt.Errorf("expression is required")
+	}
+	return nil
+}
+
+func validateReasonRule(r RuleConfig) error {
+	if len(r.Reasons) == 0 {
+		return fmt.Errorf("reasons is required")
+	}
+	for _, reason := range r.Reasons {
+		if !slices.Contains(cleaner.Reasons, reason) {
+			return fmt.Errorf("unknown reason %q", reason)
+		}
+	}
+	return nil
+}
+
+func validateReleaseRule(r RuleConfig) error {
+	if !r.Prereleases && !r.Drafts && r.maxAge(r.Hours, time.Hour) == 0 && !r.OnlyMajorMinor {
+		return fmt.Errorf("at least one of prereleases, drafts, hours, older_than or only_major_minor is required")
+	}
+	return nil
+}
+
+func validateDiscussionRule(r RuleConfig) error {
+	if !r.Closed && !r.Locked && !r.Answered {
+		return fmt.Errorf("at least one of closed, locked or answered is required")
+	}
+	return nil
+}
+
+func validateCheckSuiteRule(r RuleConfig) error {
+	if !r.Succeeded && !r.BranchGone {
+		return fmt.Errorf("at least one of succeeded or branch_gone is required")
+	}
+	return nil
+}
+
+func validateCommitRule(r RuleConfig) error {
+	if r.maxAge(r.Days, 24*time.Hour) == 0 && !r.DefaultBranch {
+		return fmt.Errorf("at least one of days, older_than or default_branch is required")
+	}
+	return nil
+}
+
+func validateRepoRule(r RuleConfig) error {
+	if len(r.Repos) == 0 {
+		return fmt.Errorf("repos is required")
+	}
+	for _, pattern := range r.Repos {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid repository pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func validateLabelRule(r RuleConfig) error {
+	if len(r.Labels) == 0 {
+		return fmt.Errorf("labels is required")
+	}
+	return nil
+}
+
+func validateReadRule(r RuleConfig) error {
+	if r.maxAge(r.Days, 24*time.Hour) == 0 {
+		return fmt.Errorf("days or older_than is required")
+	}
+	return nil
+}
+
+func validateSubjectRule(r RuleConfig) error {
+	if r.OnLocked == "" && r.OnTransferred == "" && r.OnConverted == "" {
+		return fmt.Errorf("at least one of on_locked, on_transferred or on_converted is required")
+	}
+	for _, action := range []string{r.OnLocked, r.OnTransferred, r.OnConverted} {
+		if _, err := parseAction(action); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildAgeRule(c *Config, r RuleConfig) (cleaner.Rule, error) {
+	maxAge := r.maxAge(r.Days, 24*time.Hour)
+	if maxAge == 0 {
+		maxAge = c.Threshold()
+	}
+	var overrides []cleaner.AgeOverride
+	for _, t := range c.Thresholds {
+		overrides = append(overrides, cleaner.AgeOverride{
+			Pattern: t.Repo,
+			MaxAge:  t.maxAge(),
+		})
+	}
+	rule := cleaner.NewAgeRule(maxAge, overrides...)
+	rule.Timestamp = cleaner.Timestamp(r.Timestamp)
+	return rule, nil
+}
+
+func buildClosedRule(c *Config, r RuleConfig) (cleaner.Rule, error) {
+	var states []cleaner.ClosedState
+	for _, state := range r.States {
+		states = append(states, cleaner.ClosedState(state))
+	}
+	rule := cleaner.NewClosedSubjectRule(states...)
+	rule.Ignore = c.IgnoreClosed
+	return rule, nil
+}
+
+func buildExpressionRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	return cleaner.NewExpressionRule(r.Expression)
+}
+
+func buildReasonRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	outcome, err := r.outcome()
+	if err != nil {
+		return nil, err
+	}
+	return cleaner.NewReasonRule(r.Reasons, r.maxAge(r.Days, 24*time.Hour), outcome), nil
+}
+
+func buildReleaseRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	return &cleaner.ReleaseRule{
+		Prereleases:        r.Prereleases,
+		Drafts:             r.Drafts,
+		MaxAge:             r.maxAge(r.Hours, time.Hour),
+		KeepOnlyMajorMinor: r.OnlyMajorMinor,
+	}, nil
+}
+
+func buildDiscussionRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	return &cleaner.DiscussionRule{
+		Closed:   r.Closed,
+		Locked:   r.Locked,
+		Answered: r.Answered,
+	}, nil
+}
+
+func buildCheckSuiteRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	return &cleaner.CheckSuiteRule{
+		Succeeded:  r.Succeeded,
+		BranchGone: r.BranchGone,
+	}, nil
+}
+
+func buildSecurityRule(_ *Config, _ RuleConfig) (cleaner.Rule, error) {
+	return cleaner.NewSecurityAlertRule(), nil
+}
+
+func buildCommitRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	return &cleaner.CommitRule{
+		MaxAge:          r.maxAge(r.Days, 24*time.Hour),
+		OnDefaultBranch: r.DefaultBranch,
+	}, nil
+}
+
+func buildDraftRule(_ *Config, _ RuleConfig) (cleaner.Rule, error) {
+	return cleaner.NewDraftRule(), nil
+}
+
+func buildBotRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	return &cleaner.BotRule{
+		Logins: r.Logins,
+		MaxAge: r.maxAge(r.Hours, time.Hour),
+	}, nil
+}
+
+func buildLabelRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	outcome, err := r.outcome()
+	if err != nil {
+		return nil, err
+	}
+	return cleaner.NewLabelRule(r.Labels, outcome), nil
+}
+
+func buildReviewRule(_ *Config, _ RuleConfig) (cleaner.Rule, error) {
+	return cleaner.NewReviewRequestRule(), nil
+}
+
+func buildSubjectRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	rule := &cleaner.SubjectStatusRule{}
+	var err error
+	if rule.Locked, err = parseAction(r.OnLocked); err != nil {
+		return nil, err
+	}
+	if rule.Transferred, err = parseAction(r.OnTransferred); err != nil {
+		return nil, err
+	}
+	if rule.Converted, err = parseAction(r.OnConverted); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func buildReadRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	return &cleaner.ReadRule{
+		Unread: r.Unread,
+		MaxAge: r.maxAge(r.Days, 24*time.Hour),
+	}, nil
+}
+
+func buildRepoRule(_ *Config, r RuleConfig) (cleaner.Rule, error) {
+	outcome, err := r.outcome()
+	if err != nil {
+		return nil, err
+	}
+	return cleaner.NewRepoRule(r.Repos, r.maxAge(r.Days, 24*time.Hour), outcome), nil
+}